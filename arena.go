@@ -0,0 +1,76 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"strings"
+
+	"github.com/adan-ea/GoSnakeGo/arena"
+	"github.com/adan-ea/GoSnakeGo/sim"
+)
+
+// runArena plays a game between the Battlesnake servers given as arguments,
+// each one either a URL or name=URL
+func runArena(args []string) error {
+	fs := flag.NewFlagSet("arena", flag.ExitOnError)
+	size := fs.String("size", "small", "board size: small, medium, large, extralarge or WIDTHxHEIGHT")
+	wrap := fs.Bool("wrap", false, "snakes come back on the other side of the board")
+	seed := fs.Uint64("seed", 0, "seed of the game, random when not set")
+	timeout := fs.Duration("timeout", arena.DefaultTimeout, "time a snake has to answer a move")
+	turns := fs.Int("turns", arena.DefaultMaxTurns, "turns after which the game is a draw")
+	logPath := fs.String("log", "", "file the JSON game log is written to")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "usage: gosnakego arena [flags] [name=]url...")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+
+	opts := arena.Options{Seed: sim.NewSeed(), Timeout: *timeout, MaxTurns: *turns}
+	fs.Visit(func(f *flag.Flag) {
+		if f.Name == "seed" {
+			opts.Seed = *seed
+		}
+	})
+	var board sim.Config
+	if err := board.SetSize(*size); err != nil {
+		return err
+	}
+	opts.Size, opts.Rows, opts.Cols = board.Size, board.Rows, board.Cols
+	if *wrap {
+		opts.Walls = sim.WrapWalls
+	}
+
+	var snakes []arena.Snake
+	for i, arg := range fs.Args() {
+		// a URL's query can have an = too, names come before the scheme
+		name, url, ok := strings.Cut(arg, "=")
+		if !ok || strings.Contains(name, "://") {
+			name, url = fmt.Sprintf("Snake %d", i+1), arg
+		}
+		snakes = append(snakes, arena.Snake{Name: name, URL: url})
+	}
+
+	l, err := arena.Run(context.Background(), opts, snakes)
+	if err != nil {
+		return err
+	}
+	if *logPath != "" {
+		if err := l.Save(*logPath); err != nil {
+			return err
+		}
+	}
+
+	fmt.Printf("seed %d, %d turns\n", opts.Seed, len(l.Turns))
+	for i, r := range l.Result {
+		status := "dead"
+		if r.Alive {
+			status = "alive"
+		}
+		fmt.Printf("%s: %s, score %d, length %d\n", snakes[i].Name, status, r.Score, r.Length)
+	}
+	if l.Winner >= 0 {
+		fmt.Printf("%s wins\n", snakes[l.Winner].Name)
+	}
+	return nil
+}
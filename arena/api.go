@@ -0,0 +1,138 @@
+package arena
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/adan-ea/GoSnakeGo/sim"
+)
+
+// Our rules have no hunger, every snake always has full health
+const fullHealth = 100
+
+// gameState is the body of the requests sent to the snakes, see
+// https://docs.battlesnake.com/api
+type gameState struct {
+	Game  gameInfo   `json:"game"`
+	Turn  int        `json:"turn"`
+	Board boardState `json:"board"`
+	You   snakeState `json:"you"`
+}
+
+type gameInfo struct {
+	ID      string      `json:"id"`
+	Ruleset rulesetInfo `json:"ruleset"`
+	Map     string      `json:"map"`
+	Source  string      `json:"source"`
+	Timeout int         `json:"timeout"` // milliseconds a snake has to answer
+}
+
+type rulesetInfo struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+}
+
+type boardState struct {
+	Height  int          `json:"height"`
+	Width   int          `json:"width"`
+	Food    []coord      `json:"food"`
+	Hazards []coord      `json:"hazards"`
+	Snakes  []snakeState `json:"snakes"`
+}
+
+type snakeState struct {
+	ID      string  `json:"id"`
+	Name    string  `json:"name"`
+	Health  int     `json:"health"`
+	Body    []coord `json:"body"` // head first
+	Head    coord   `json:"head"`
+	Length  int     `json:"length"`
+	Latency string  `json:"latency"`
+	Shout   string  `json:"shout"`
+}
+
+// coord is a position in Battlesnake coordinates, where y grows upward
+type coord struct {
+	X int `json:"x"`
+	Y int `json:"y"`
+}
+
+// moveResponse is what a snake answers to /move
+type moveResponse struct {
+	Move  string `json:"move"`
+	Shout string `json:"shout,omitempty"`
+}
+
+// toCoord converts a board position, where y grows downward
+func toCoord(p sim.Point, rows int) coord {
+	return coord{X: p.X, Y: rows - 1 - p.Y}
+}
+
+// state returns the game as seen by the i-th snake
+func (e *engine) state(you int) gameState {
+	b := e.board
+	rows := b.Rows()
+
+	var food []coord
+	if f := b.Food(); f != nil {
+		food = append(food, toCoord(f.Pos(), rows))
+	}
+	st := gameState{
+		Game: gameInfo{
+			ID: e.id,
+			Ruleset: rulesetInfo{
+				Name:    rulesetName,
+				Version: strconv.Itoa(sim.RulesVersion),
+			},
+			Map:     "",
+			Source:  "custom",
+			Timeout: int(e.opts.Timeout.Milliseconds()),
+		},
+		Turn: e.turn,
+		Board: boardState{
+			Height: rows,
+			Width:  b.Cols(),
+			Food:   food,
+			// arena boards have no level: Battlesnake hazards are tiles that
+			// cost health but can be walked on, they cannot stand for walls
+			Hazards: []coord{},
+		},
+	}
+	for i, s := range b.Snakes() {
+		snake := e.snakeState(i)
+		if i == you {
+			st.You = snake
+		}
+		// the board only holds the snakes still playing
+		if s.Alive() {
+			st.Board.Snakes = append(st.Board.Snakes, snake)
+		}
+	}
+	return st
+}
+
+// snakeState returns the i-th snake in Battlesnake coordinates
+func (e *engine) snakeState(i int) snakeState {
+	s := e.board.Snakes()[i]
+	rows := e.board.Rows()
+
+	// our bodies start at the tail
+	body := make([]coord, len(s.Body()))
+	for j, p := range s.Body() {
+		body[len(body)-1-j] = toCoord(p, rows)
+	}
+	return snakeState{
+		ID:      snakeID(i),
+		Name:    e.snakes[i].Name,
+		Health:  fullHealth,
+		Body:    body,
+		Head:    body[0],
+		Length:  len(body),
+		Latency: strconv.FormatInt(e.snakes[i].latency.Milliseconds(), 10),
+		Shout:   e.snakes[i].shout,
+	}
+}
+
+func snakeID(i int) string {
+	return fmt.Sprintf("snake-%d", i+1)
+}
@@ -0,0 +1,238 @@
+// Package arena runs games between remote snakes that implement the
+// Battlesnake HTTP API, using the rules of the game
+package arena
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/adan-ea/GoSnakeGo/sim"
+)
+
+// Name of our rules sent to the snakes
+const rulesetName = "gosnakego"
+
+// Most snakes on a board
+const MaxSnakes = sim.MaxSnakes
+
+// Default limits of a game
+const (
+	DefaultTimeout  = 500 * time.Millisecond
+	DefaultMaxTurns = 10000
+)
+
+// Snake is a remote snake taking part in a game
+type Snake struct {
+	Name string `json:"name"`
+	URL  string `json:"url"` // base URL of the snake's server
+
+	latency time.Duration // time taken to answer the last move
+	shout   string
+}
+
+// Options holds the settings of a game
+type Options struct {
+	Size     sim.Size
+	Rows     int // rows of a sim.CustomSize board
+	Cols     int // columns of a sim.CustomSize board
+	Walls    sim.Walls
+	Seed     uint64
+	Timeout  time.Duration // time a snake has to answer a move, DefaultTimeout when zero
+	MaxTurns int           // the game is a draw after this many turns, DefaultMaxTurns when zero
+	Client   *http.Client  // http.DefaultClient when nil
+}
+
+// engine plays one game
+type engine struct {
+	id     string
+	opts   Options
+	snakes []*Snake
+	board  *sim.Board
+	turn   int
+	log    *Log
+}
+
+// Run plays a game between the given snakes and returns its log. Snakes that
+// fail to answer a move in time, or answer with an invalid move, keep going
+// straight.
+func Run(ctx context.Context, opts Options, snakes []Snake) (*Log, error) {
+	if len(snakes) == 0 || len(snakes) > MaxSnakes {
+		return nil, fmt.Errorf("a game needs between 1 and %d snakes", MaxSnakes)
+	}
+	if opts.Timeout <= 0 {
+		opts.Timeout = DefaultTimeout
+	}
+	if opts.MaxTurns <= 0 {
+		opts.MaxTurns = DefaultMaxTurns
+	}
+	if opts.Client == nil {
+		opts.Client = http.DefaultClient
+	}
+
+	cfg := sim.Config{Size: opts.Size, Rows: opts.Rows, Cols: opts.Cols, Walls: opts.Walls, Seed: opts.Seed}
+	for range snakes {
+		cfg.Snakes = append(cfg.Snakes, sim.SnakeConfig{Color: sim.RandomColor})
+	}
+
+	e := &engine{
+		id:    fmt.Sprintf("%016x", opts.Seed),
+		opts:  opts,
+		board: sim.NewBoard(cfg),
+	}
+	for _, s := range snakes {
+		s.URL = strings.TrimSuffix(s.URL, "/")
+		e.snakes = append(e.snakes, &s)
+	}
+	e.log = newLog(e.id, cfg, e.snakes)
+
+	e.broadcast(ctx, "/start")
+	for !e.board.GameOver() && e.turn < opts.MaxTurns {
+		if err := ctx.Err(); err != nil {
+			return e.log, err
+		}
+		e.step(ctx)
+	}
+	e.broadcast(ctx, "/end")
+
+	e.log.finish(e.board)
+	return e.log, nil
+}
+
+// step asks every snake still playing for its move and moves them all
+func (e *engine) step(ctx context.Context) {
+	snakes := e.board.Snakes()
+	actions := make([]sim.Action, len(snakes))
+	moves := make([]MoveLog, len(snakes))
+
+	// every snake sees the board as it was before anyone answered
+	states := make([]gameState, len(snakes))
+	for i := range snakes {
+		states[i] = e.state(i)
+	}
+
+	var wg sync.WaitGroup
+	for i, s := range snakes {
+		if !s.Alive() {
+			continue
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			moves[i] = e.move(ctx, i, states[i], s.Direction())
+		}()
+	}
+	wg.Wait()
+
+	turn := TurnLog{Turn: e.turn}
+	for i, m := range moves {
+		if !snakes[i].Alive() {
+			continue
+		}
+		// Battlesnake's up is toward the top of the board like ours even
+		// though its y axis is flipped
+		dir, _ := sim.ParseDirection(m.Move)
+		actions[i] = sim.Action{Turn: true, Dir: dir}
+		turn.Moves = append(turn.Moves, m)
+	}
+
+	for _, ev := range e.board.Step(actions...) {
+		turn.Events = append(turn.Events, newEventLog(ev))
+	}
+	e.turn++
+	e.log.Turns = append(e.log.Turns, turn)
+}
+
+// move asks the i-th snake for its move, falling back to its current
+// direction when it does not answer in time or answers nonsense
+func (e *engine) move(ctx context.Context, i int, st gameState, current sim.Direction) MoveLog {
+	snake := e.snakes[i]
+	m := MoveLog{Snake: i, Move: current.String()}
+
+	ctx, cancel := context.WithTimeout(ctx, e.opts.Timeout)
+	defer cancel()
+
+	var res moveResponse
+	start := time.Now()
+	err := e.post(ctx, snake.URL+"/move", st, &res)
+	snake.latency = time.Since(start)
+	m.Latency = snake.latency.Milliseconds()
+
+	switch {
+	case errors.Is(err, context.DeadlineExceeded):
+		m.TimedOut = true
+		m.Error = "no answer in time"
+	case err != nil:
+		m.Error = err.Error()
+	default:
+		dir, err := sim.ParseDirection(res.Move)
+		if err != nil {
+			m.Error = err.Error()
+			break
+		}
+		m.Move = dir.String()
+		m.Shout = res.Shout
+		snake.shout = res.Shout
+	}
+	return m
+}
+
+// broadcast sends the game to every snake on the given path, ignoring their
+// answers
+func (e *engine) broadcast(ctx context.Context, path string) {
+	errs := make([]error, len(e.snakes))
+	var wg sync.WaitGroup
+	for i, s := range e.snakes {
+		st := e.state(i)
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			ctx, cancel := context.WithTimeout(ctx, e.opts.Timeout)
+			defer cancel()
+			errs[i] = e.post(ctx, s.URL+path, st, nil)
+		}()
+	}
+	wg.Wait()
+
+	for i, err := range errs {
+		if err != nil {
+			e.log.Errors = append(e.log.Errors, fmt.Sprintf("%s %s: %v", e.snakes[i].Name, path, err))
+		}
+	}
+}
+
+// post sends body as JSON to url and decodes the answer into res unless it
+// is nil
+func (e *engine) post(ctx context.Context, url string, body, res any) error {
+	data, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := e.opts.Client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("%s answered %s", url, resp.Status)
+	}
+	if res == nil {
+		_, err = io.Copy(io.Discard, resp.Body)
+		return err
+	}
+	return json.NewDecoder(resp.Body).Decode(res)
+}
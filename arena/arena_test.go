@@ -0,0 +1,168 @@
+package arena
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/adan-ea/GoSnakeGo/sim"
+)
+
+// standIn is a snake server answering every move with the same direction
+type standIn struct {
+	*httptest.Server
+	move  string
+	delay time.Duration // time taken to answer any request
+
+	mu       sync.Mutex
+	paths    []string    // paths requested in order
+	requests []gameState // bodies of the /move requests
+}
+
+func newStandIn(t *testing.T, move string, delay time.Duration) *standIn {
+	s := &standIn{move: move, delay: delay}
+	s.Server = httptest.NewServer(http.HandlerFunc(s.serve))
+	t.Cleanup(s.Close)
+	return s
+}
+
+func (s *standIn) serve(w http.ResponseWriter, r *http.Request) {
+	var st gameState
+	if err := json.NewDecoder(r.Body).Decode(&st); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	s.mu.Lock()
+	s.paths = append(s.paths, r.URL.Path)
+	if r.URL.Path == "/move" {
+		s.requests = append(s.requests, st)
+	}
+	s.mu.Unlock()
+
+	select {
+	case <-time.After(s.delay):
+	case <-r.Context().Done():
+		return
+	}
+	if r.URL.Path == "/move" {
+		json.NewEncoder(w).Encode(moveResponse{Move: s.move, Shout: "going " + s.move})
+	}
+}
+
+func TestRun(t *testing.T) {
+	up, left := newStandIn(t, "up", 0), newStandIn(t, "left", 0)
+
+	log, err := Run(context.Background(), Options{Size: sim.Small, Seed: 1}, []Snake{
+		{Name: "up", URL: up.URL},
+		{Name: "left", URL: left.URL + "/"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the first snake starts on the second row and leaves the board on its
+	// second move, the second one starts at its mirror image on the
+	// second-to-last row
+	if len(log.Turns) != 2 {
+		t.Fatalf("played %d turns, want 2", len(log.Turns))
+	}
+	if log.Winner != 1 {
+		t.Errorf("winner %d, want 1", log.Winner)
+	}
+	if log.Result[0].Alive || !log.Result[1].Alive {
+		t.Errorf("result %+v, want only the second snake alive", log.Result)
+	}
+	died := log.Turns[1].Events
+	if len(died) != 1 || died[0].Kind != "died" || died[0].Snake != 0 || died[0].Cause != "wall" {
+		t.Errorf("last turn events %+v, want the first snake hitting a wall", died)
+	}
+	for _, m := range log.Turns[0].Moves {
+		if m.Error != "" || m.TimedOut || m.Shout != "going "+m.Move {
+			t.Errorf("move %+v, want an answer", m)
+		}
+	}
+	if len(log.Errors) > 0 {
+		t.Errorf("errors %v", log.Errors)
+	}
+
+	for _, s := range []*standIn{up, left} {
+		want := []string{"/start", "/move", "/move", "/end"}
+		if strings.Join(s.paths, " ") != strings.Join(want, " ") {
+			t.Errorf("%s requested %v, want %v", s.move, s.paths, want)
+		}
+	}
+
+	// the snakes see the board with y growing upward
+	first := up.requests[0]
+	if first.You.ID != "snake-1" || first.Board.Width != 14 || len(first.Board.Snakes) != 2 {
+		t.Errorf("first request %+v", first)
+	}
+	if head := first.You.Head; head != (coord{X: 3, Y: 12}) {
+		t.Errorf("first snake head at %v, want {3 12}", head)
+	}
+}
+
+func TestRunTimeout(t *testing.T) {
+	slow, fast := newStandIn(t, "left", time.Second), newStandIn(t, "left", 0)
+
+	log, err := Run(context.Background(), Options{
+		Size:     sim.Small,
+		Seed:     1,
+		Timeout:  20 * time.Millisecond,
+		MaxTurns: 3,
+	}, []Snake{
+		{Name: "slow", URL: slow.URL},
+		{Name: "fast", URL: fast.URL},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(log.Turns) != 3 {
+		t.Fatalf("played %d turns, want 3", len(log.Turns))
+	}
+	for _, turn := range log.Turns {
+		// the slow snake keeps going right, its starting direction
+		if m := turn.Moves[0]; !m.TimedOut || m.Error == "" || m.Move != "right" {
+			t.Errorf("turn %d: slow move %+v, want a timeout going right", turn.Turn, m)
+		}
+		if m := turn.Moves[1]; m.TimedOut || m.Move != "left" {
+			t.Errorf("turn %d: fast move %+v, want left in time", turn.Turn, m)
+		}
+	}
+	if !log.Result[0].Alive || !log.Result[1].Alive {
+		t.Errorf("result %+v, want both snakes alive", log.Result)
+	}
+
+	// /start and /end of the slow snake time out too
+	if len(log.Errors) != 2 || !strings.HasPrefix(log.Errors[0], "slow /start") || !strings.HasPrefix(log.Errors[1], "slow /end") {
+		t.Errorf("errors %q, want the slow snake's /start and /end", log.Errors)
+	}
+}
+
+func TestRunInvalidMove(t *testing.T) {
+	lost := newStandIn(t, "sideways", 0)
+
+	log, err := Run(context.Background(), Options{Size: sim.Small, Seed: 1, MaxTurns: 1}, []Snake{
+		{Name: "lost", URL: lost.URL},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if m := log.Turns[0].Moves[0]; m.TimedOut || m.Error == "" || m.Move != "right" {
+		t.Errorf("move %+v, want an error going right", m)
+	}
+}
+
+func TestRunSnakeCount(t *testing.T) {
+	for _, n := range []int{0, MaxSnakes + 1} {
+		if _, err := Run(context.Background(), Options{}, make([]Snake, n)); err == nil {
+			t.Errorf("played a game of %d snakes", n)
+		}
+	}
+}
@@ -0,0 +1,100 @@
+package arena
+
+import (
+	"encoding/json"
+	"os"
+
+	"github.com/adan-ea/GoSnakeGo/sim"
+)
+
+// Log is the record of a game between remote snakes
+type Log struct {
+	ID      string     `json:"id"`
+	Version int        `json:"version"` // rules version the game was played with
+	Config  sim.Config `json:"config"`  // config of the board, replaying the moves on it gives the same game
+	Snakes  []*Snake   `json:"snakes"`
+	Turns   []TurnLog  `json:"turns"`
+	// index of the last snake alive, -1 for a draw or a game with one snake
+	Winner int        `json:"winner"`
+	Result []SnakeLog `json:"result"`
+	Errors []string   `json:"errors,omitempty"` // failed /start and /end requests
+}
+
+// TurnLog holds the moves of a turn and what happened after them
+type TurnLog struct {
+	Turn   int        `json:"turn"`
+	Moves  []MoveLog  `json:"moves"`
+	Events []EventLog `json:"events,omitempty"`
+}
+
+// MoveLog is the answer of a snake to /move
+type MoveLog struct {
+	Snake    int    `json:"snake"`
+	Move     string `json:"move"` // move played, the snake's current direction when its answer was not used
+	Shout    string `json:"shout,omitempty"`
+	Latency  int64  `json:"latency"` // milliseconds
+	TimedOut bool   `json:"timedOut,omitempty"`
+	Error    string `json:"error,omitempty"`
+}
+
+// EventLog is something that happened to a snake during a turn
+type EventLog struct {
+	Kind  string    `json:"kind"`
+	Snake int       `json:"snake"`
+	Pos   sim.Point `json:"pos"` // board position, y growing downward
+	Cause string    `json:"cause,omitempty"`
+}
+
+// SnakeLog is how a snake ended the game
+type SnakeLog struct {
+	Alive  bool `json:"alive"`
+	Score  int  `json:"score"`
+	Length int  `json:"length"`
+}
+
+func newLog(id string, cfg sim.Config, snakes []*Snake) *Log {
+	return &Log{
+		ID:      id,
+		Version: sim.RulesVersion,
+		Config:  cfg,
+		Snakes:  snakes,
+		Winner:  -1,
+	}
+}
+
+// finish records the result of the game
+func (l *Log) finish(b *sim.Board) {
+	l.Winner = b.Winner()
+	for _, s := range b.Snakes() {
+		l.Result = append(l.Result, SnakeLog{
+			Alive:  s.Alive(),
+			Score:  s.Score(),
+			Length: len(s.Body()),
+		})
+	}
+}
+
+// Save writes the log to the given file as indented JSON
+func (l *Log) Save(path string) error {
+	data, err := json.MarshalIndent(l, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0o644)
+}
+
+func newEventLog(ev sim.Event) EventLog {
+	e := EventLog{Snake: ev.Snake, Pos: ev.Pos}
+	switch ev.Kind {
+	case sim.EventAte:
+		e.Kind = "ate"
+	case sim.EventDied:
+		e.Kind = "died"
+		e.Cause = ev.Cause.String()
+	case sim.EventWon:
+		e.Kind = "won"
+	default:
+		e.Kind = "other"
+	}
+	return e
+}
@@ -0,0 +1,171 @@
+package bot
+
+import (
+	"container/heap"
+
+	"github.com/adan-ea/GoSnakeGo/sim"
+)
+
+// Autopilot is a controller that plays a snake alone on the board. It takes
+// the shortest path to the food when the snake can still reach its tail once
+// there, and follows its tail otherwise. With a Hamiltonian cycle it goes
+// through every tile of the board in turn, taking shortcuts toward the food
+// while the snake is short, so it can fill the board.
+type Autopilot struct {
+	hamiltonian bool
+	cycle       *cycle // built for the board on the first move
+}
+
+// NewAutopilot creates an autopilot, following a Hamiltonian cycle when
+// hamiltonian is set and the board has one
+func NewAutopilot(hamiltonian bool) *Autopilot {
+	return &Autopilot{hamiltonian: hamiltonian}
+}
+
+// Action returns the input of the snake at the given index for the next tick
+func (a *Autopilot) Action(v sim.View, snake int) sim.Action {
+	s := v.Snakes()[snake]
+	if !s.Alive() || s.Turning() {
+		return sim.Action{}
+	}
+
+	g := newGrid(v)
+	dir, ok := a.cycleMove(g, s)
+	if !ok {
+		dir = pathMove(g, s)
+	}
+
+	if dir == s.Direction() {
+		return sim.Action{}
+	}
+	return sim.Action{Turn: true, Dir: dir}
+}
+
+// cycleMove returns the next move along the Hamiltonian cycle, false when
+// there is no cycle to follow
+func (a *Autopilot) cycleMove(g *grid, s *sim.Snake) (sim.Direction, bool) {
+	if !a.hamiltonian {
+		return 0, false
+	}
+	if a.cycle == nil || a.cycle.rows != g.board.Rows() || a.cycle.cols != g.board.Cols() {
+		a.cycle = newCycle(g.board)
+	}
+	if a.cycle.order == nil {
+		return 0, false
+	}
+
+	return a.cycle.move(g, s)
+}
+
+// pathMove goes to the food when it is safe to, and after the tail otherwise
+func pathMove(g *grid, s *sim.Snake) sim.Direction {
+	food := g.board.Food().Pos()
+	if path := g.aStar(s.Head(), food, s.Direction()); path != nil && safeAfter(g, s, path) {
+		return path[0]
+	}
+
+	tail := s.Body()[0]
+	if path := g.aStar(s.Head(), tail, s.Direction()); path != nil {
+		return path[0]
+	}
+	return cautious(g, s)
+}
+
+// safeAfter reports whether the snake can still reach its tail once it has
+// followed the path and eaten the food at its end
+func safeAfter(g *grid, s *sim.Snake, path []sim.Direction) bool {
+	body := append([]sim.Point(nil), s.Body()...)
+	growth := max(g.board.Food().Kind().Type().Growth, 0)
+
+	p := s.Head()
+	for i, dir := range path {
+		p, _ = g.next(p, dir)
+		body = append(body, p)
+		// the snake only grows once the food is eaten, at the end of the path
+		if i < len(path)-1 || growth == 0 {
+			body = body[1:]
+		} else {
+			growth--
+		}
+	}
+
+	// see where the snake would be, other snakes and walls staying put
+	after := &grid{board: g.board, blocked: make(map[sim.Point]bool)}
+	for q := range g.blocked {
+		after.blocked[q] = true
+	}
+	for _, q := range s.Body() {
+		delete(after.blocked, q)
+	}
+	for _, q := range body {
+		after.blocked[q] = true
+	}
+
+	head, tail := body[len(body)-1], body[0]
+	delete(after.blocked, tail)
+	return after.aStar(head, tail, path[len(path)-1]) != nil
+}
+
+// aStar returns the moves of a shortest path between two tiles going
+// through free tiles only, the target being reachable even when it is not
+// free. It returns nil when there is no such path.
+func (g *grid) aStar(from, to sim.Point, dir sim.Direction) []sim.Direction {
+	type step struct {
+		prev sim.Point
+		dir  sim.Direction
+	}
+	came := map[sim.Point]step{}
+	cost := map[sim.Point]int{from: 0}
+
+	open := &pointHeap{}
+	heap.Push(open, scored{p: from, score: g.distance(from, to)})
+	for open.Len() > 0 {
+		curr := heap.Pop(open).(scored).p
+		if curr == to {
+			var path []sim.Direction
+			for curr != from {
+				path = append([]sim.Direction{came[curr].dir}, path...)
+				curr = came[curr].prev
+			}
+			return path
+		}
+
+		for _, d := range directions {
+			// the snake cannot reverse on its first move
+			if curr == from && d == dir.Opposite() {
+				continue
+			}
+			n, ok := g.next(curr, d)
+			if !ok || (n != to && !g.free(n)) {
+				continue
+			}
+			if c, seen := cost[n]; seen && c <= cost[curr]+1 {
+				continue
+			}
+			cost[n] = cost[curr] + 1
+			came[n] = step{prev: curr, dir: d}
+			heap.Push(open, scored{p: n, score: cost[n] + g.distance(n, to)})
+		}
+	}
+	return nil
+}
+
+// scored is a tile waiting to be explored by aStar
+type scored struct {
+	p     sim.Point
+	score int // moves made plus the estimate of the moves left
+}
+
+// pointHeap keeps the tile with the lowest score on top
+type pointHeap []scored
+
+func (h pointHeap) Len() int           { return len(h) }
+func (h pointHeap) Less(i, j int) bool { return h[i].score < h[j].score }
+func (h pointHeap) Swap(i, j int)      { h[i], h[j] = h[j], h[i] }
+func (h *pointHeap) Push(x any)        { *h = append(*h, x.(scored)) }
+func (h *pointHeap) Pop() any {
+	old := *h
+	x := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return x
+}
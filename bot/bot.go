@@ -0,0 +1,105 @@
+// Package bot implements computer players that pick the moves of a snake
+// from the state of the board
+package bot
+
+import "github.com/adan-ea/GoSnakeGo/sim"
+
+// Difficulty is how a bot picks its moves
+type Difficulty int
+
+// Number of difficulties available
+const NbDifficulties = 3
+const (
+	Greedy      Difficulty = iota // heads straight for the food
+	Cautious                      // goes for the food without getting trapped or meeting bigger heads
+	Pathfinding                   // follows the shortest path to the food, cautiously when there is none
+)
+
+// Bot is a controller playing a snake on its own
+type Bot struct {
+	difficulty Difficulty
+}
+
+// New creates a bot of the given difficulty
+func New(difficulty Difficulty) *Bot {
+	return &Bot{difficulty: difficulty}
+}
+
+// Difficulty returns how the bot picks its moves
+func (b *Bot) Difficulty() Difficulty {
+	return b.difficulty
+}
+
+// Action returns the input of the snake at the given index for the next
+// tick. The bot waits for its last turn to be applied before picking the
+// next one.
+func (b *Bot) Action(v sim.View, snake int) sim.Action {
+	s := v.Snakes()[snake]
+	if !s.Alive() || s.Turning() {
+		return sim.Action{}
+	}
+
+	dir := b.move(newGrid(v), s)
+	if dir == s.Direction() {
+		return sim.Action{}
+	}
+	return sim.Action{Turn: true, Dir: dir}
+}
+
+// move returns the direction the snake should go in
+func (b *Bot) move(g *grid, s *sim.Snake) sim.Direction {
+	switch b.difficulty {
+	case Cautious:
+		return cautious(g, s)
+	case Pathfinding:
+		if dir, ok := g.path(s, g.board.Food().Pos()); ok && roomy(g, s, dir) {
+			return dir
+		}
+		return cautious(g, s)
+	}
+	return greedy(g, s)
+}
+
+// greedy returns the safe move that gets closest to the food
+func greedy(g *grid, s *sim.Snake) sim.Direction {
+	best, bestDist := s.Direction(), -1
+	for _, dir := range g.safeMoves(s) {
+		p, _ := g.next(s.Head(), dir)
+		if d := g.distance(p, g.board.Food().Pos()); bestDist < 0 || d < bestDist {
+			best, bestDist = dir, d
+		}
+	}
+	return best
+}
+
+// cautious returns the safe move that gets closest to the food, preferring
+// moves that leave enough room for the snake and keep away from the heads
+// of bigger snakes. Without such a move it goes where there is the most room.
+func cautious(g *grid, s *sim.Snake) sim.Direction {
+	best, bestDist := s.Direction(), -1
+	roomiest, bestSpace := s.Direction(), -1
+	for _, dir := range g.safeMoves(s) {
+		p, _ := g.next(s.Head(), dir)
+		if space := g.space(p, len(s.Body())); space > bestSpace {
+			roomiest, bestSpace = dir, space
+		}
+		if !roomy(g, s, dir) || g.dangerous(s, p) {
+			continue
+		}
+		if d := g.distance(p, g.board.Food().Pos()); bestDist < 0 || d < bestDist {
+			best, bestDist = dir, d
+		}
+	}
+
+	if bestDist < 0 {
+		return roomiest
+	}
+	return best
+}
+
+// roomy reports whether moving in the given direction leaves the snake at
+// least as many free tiles as it has segments
+func roomy(g *grid, s *sim.Snake, dir sim.Direction) bool {
+	p, _ := g.next(s.Head(), dir)
+	return g.space(p, len(s.Body())) >= len(s.Body())
+}
@@ -0,0 +1,109 @@
+package bot_test
+
+import (
+	"testing"
+
+	"github.com/adan-ea/GoSnakeGo/bot"
+	"github.com/adan-ea/GoSnakeGo/sim"
+)
+
+var difficulties = []bot.Difficulty{bot.Greedy, bot.Cautious, bot.Pathfinding}
+
+// boardWith returns a small board holding the given snakes with the food at
+// the given tile
+func boardWith(t *testing.T, food sim.Point, snakes ...sim.SnakeState) *sim.Board {
+	t.Helper()
+	st, err := sim.NewBoard(sim.Config{Size: sim.Small, Seed: 1}).Snapshot()
+	if err != nil {
+		t.Fatal(err)
+	}
+	st.Snakes = snakes
+	st.Food = sim.FoodState{Pos: food}
+
+	b, err := sim.RestoreBoard(st)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return b
+}
+
+// move returns the direction the controller sends the first snake in
+func move(c sim.Controller, b *sim.Board) sim.Direction {
+	if action := c.Action(b, 0); action.Turn {
+		return action.Dir
+	}
+	return b.Snake().Direction()
+}
+
+func TestBotsTurnBeforeTheWall(t *testing.T) {
+	for _, d := range difficulties {
+		b := boardWith(t, sim.Point{X: 13, Y: 5},
+			sim.SnakeState{Body: []sim.Point{{X: 11, Y: 1}, {X: 12, Y: 1}, {X: 13, Y: 1}}, Direction: sim.Right})
+		if dir := move(bot.New(d), b); dir != sim.Down {
+			t.Errorf("difficulty %d moves %v, want down toward the food", d, dir)
+		}
+	}
+}
+
+func TestCautiousAvoidsBiggerHeads(t *testing.T) {
+	// the food is past the head of a longer snake, going straight could
+	// meet it head-on
+	snakes := []sim.SnakeState{
+		{Body: []sim.Point{{X: 1, Y: 5}, {X: 2, Y: 5}, {X: 3, Y: 5}}, Direction: sim.Right},
+		{Body: []sim.Point{{X: 5, Y: 8}, {X: 5, Y: 7}, {X: 5, Y: 6}, {X: 5, Y: 5}}, Direction: sim.Up, Bot: true},
+	}
+
+	if dir := move(bot.New(bot.Greedy), boardWith(t, sim.Point{X: 8, Y: 5}, snakes...)); dir != sim.Right {
+		t.Errorf("greedy moves %v, want right toward the food", dir)
+	}
+	if dir := move(bot.New(bot.Cautious), boardWith(t, sim.Point{X: 8, Y: 5}, snakes...)); dir == sim.Right {
+		t.Error("cautious moves next to the longer head")
+	}
+}
+
+// play plays a solo game with the controller and returns the board it
+// ended on
+func play(c sim.Controller, cfg sim.Config) *sim.Board {
+	b := sim.NewBoard(cfg)
+	for moves := 0; !b.GameOver() && moves < 20000; moves++ {
+		b.Step(c.Action(b, 0))
+	}
+	return b
+}
+
+func TestDifficultiesScore(t *testing.T) {
+	const games = 20
+	totals := map[bot.Difficulty]int{}
+	for _, d := range difficulties {
+		for seed := range uint64(games) {
+			b := play(bot.New(d), sim.Config{Size: sim.Small, Seed: seed})
+			if b.Score() < 5 {
+				t.Errorf("difficulty %d scores %d with seed %d", d, b.Score(), seed)
+			}
+			totals[d] += b.Score()
+		}
+	}
+
+	// the careful difficulties live longer than the greedy one
+	if totals[bot.Cautious] <= totals[bot.Greedy] || totals[bot.Pathfinding] <= totals[bot.Greedy] {
+		t.Errorf("total scores of %d games %v, want greedy last", games, totals)
+	}
+}
+
+func TestHamiltonianAutopilotFillsTheBoard(t *testing.T) {
+	for seed := range uint64(3) {
+		b := play(bot.NewAutopilot(true), sim.Config{Size: sim.CustomSize, Rows: 8, Cols: 8, Seed: seed})
+		if !b.Won() {
+			t.Errorf("seed %d: the autopilot scored %d without filling the board", seed, b.Score())
+		}
+	}
+}
+
+func TestAutopilotScores(t *testing.T) {
+	for seed := range uint64(5) {
+		b := play(bot.NewAutopilot(false), sim.Config{Size: sim.Small, Seed: seed})
+		if b.Score() < 20 {
+			t.Errorf("seed %d: the autopilot scores %d", seed, b.Score())
+		}
+	}
+}
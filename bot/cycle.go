@@ -0,0 +1,100 @@
+package bot
+
+import "github.com/adan-ea/GoSnakeGo/sim"
+
+// Shortcuts are only taken while the snake covers less than this share of the board
+const shortcutShare = 0.5
+
+// cycle is a Hamiltonian cycle going through every tile of a board once
+type cycle struct {
+	rows, cols int
+	order      map[sim.Point]int // position of each tile along the cycle, nil when there is no cycle
+}
+
+// newCycle builds a Hamiltonian cycle for the board. There is none on boards
+// with obstacles or with an odd number of rows and columns.
+func newCycle(v sim.View) *cycle {
+	c := &cycle{rows: v.Rows(), cols: v.Cols()}
+	for y := 0; y < c.rows; y++ {
+		for x := 0; x < c.cols; x++ {
+			if v.Obstacle(sim.Point{X: x, Y: y}) {
+				return c
+			}
+		}
+	}
+
+	switch {
+	case c.rows%2 == 0 && c.cols > 1:
+		c.order = serpentine(c.rows, c.cols, false)
+	case c.cols%2 == 0 && c.rows > 1:
+		c.order = serpentine(c.cols, c.rows, true)
+	}
+	return c
+}
+
+// serpentine builds a cycle that goes along the first line, then back and
+// forth over the other lines leaving the first column free, and up the first
+// column to its start. lines must be even. When transposed, lines are
+// columns instead of rows.
+func serpentine(lines, length int, transposed bool) map[sim.Point]int {
+	order := make(map[sim.Point]int, lines*length)
+	add := func(line, i int) {
+		p := sim.Point{X: i, Y: line}
+		if transposed {
+			p = sim.Point{X: line, Y: i}
+		}
+		order[p] = len(order)
+	}
+
+	for i := 0; i < length; i++ {
+		add(0, i)
+	}
+	for line := 1; line < lines; line++ {
+		for i := 1; i < length; i++ {
+			if line%2 == 1 {
+				add(line, length-i)
+			} else {
+				add(line, i)
+			}
+		}
+	}
+	for line := lines - 1; line > 0; line-- {
+		add(line, 0)
+	}
+	return order
+}
+
+// ahead returns how many tiles along the cycle there are from a to b
+func (c *cycle) ahead(a, b sim.Point) int {
+	size := len(c.order)
+	return ((c.order[b]-c.order[a])%size + size) % size
+}
+
+// move returns the next move along the cycle, or a shortcut toward the food
+// that cannot catch up with the tail
+func (c *cycle) move(g *grid, s *sim.Snake) (sim.Direction, bool) {
+	head, tail := s.Head(), s.Body()[0]
+	food := g.board.Food().Pos()
+
+	best, bestGain, found := sim.Direction(0), -1, false
+	short := float64(len(s.Body())) < shortcutShare*float64(len(c.order))
+	for _, dir := range g.safeMoves(s) {
+		n, ok := g.next(head, dir)
+		if !ok {
+			continue
+		}
+
+		gain := c.ahead(head, n)
+		if gain != 1 {
+			// a shortcut must not skip the food nor get close to the tail
+			growth := max(g.board.Food().Kind().Type().Growth, 0)
+			if !short || gain > c.ahead(head, food) || gain >= c.ahead(head, tail)-growth-3 {
+				continue
+			}
+		}
+		if gain > bestGain {
+			best, bestGain, found = dir, gain, true
+		}
+	}
+	return best, found
+}
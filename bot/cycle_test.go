@@ -0,0 +1,71 @@
+package bot
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/adan-ea/GoSnakeGo/sim"
+)
+
+func TestCycle(t *testing.T) {
+	tests := []struct {
+		name       string
+		rows, cols int
+	}{
+		{"even", 14, 14},
+		{"even rows", 8, 9},
+		{"even columns", 9, 8},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := newCycle(sim.NewBoard(sim.Config{Size: sim.CustomSize, Rows: tt.rows, Cols: tt.cols}))
+			if len(c.order) != tt.rows*tt.cols {
+				t.Fatalf("cycle through %d tiles, want %d", len(c.order), tt.rows*tt.cols)
+			}
+
+			tiles := make([]sim.Point, len(c.order))
+			for p, i := range c.order {
+				tiles[i] = p
+			}
+			for i, p := range tiles {
+				// the last tile leads back to the first one
+				n := tiles[(i+1)%len(tiles)]
+				if d := abs(p.X-n.X) + abs(p.Y-n.Y); d != 1 {
+					t.Fatalf("tile %d at %v is %d tiles from the next one at %v", i, p, d, n)
+				}
+			}
+		})
+	}
+}
+
+func TestNoCycle(t *testing.T) {
+	level, err := sim.ParseLevel(strings.NewReader(`
+........
+...>....
+........
+........
+...##...
+........
+........
+........
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name string
+		cfg  sim.Config
+	}{
+		{"odd", sim.Config{Size: sim.CustomSize, Rows: 9, Cols: 9}},
+		{"obstacles", sim.Config{Level: level}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if c := newCycle(sim.NewBoard(tt.cfg)); c.order != nil {
+				t.Errorf("cycle through %d tiles", len(c.order))
+			}
+		})
+	}
+}
@@ -0,0 +1,143 @@
+package bot
+
+import "github.com/adan-ea/GoSnakeGo/sim"
+
+// Directions in the order bots try them
+var directions = []sim.Direction{sim.Up, sim.Right, sim.Down, sim.Left}
+
+// grid is what a bot knows of the board when picking a move
+type grid struct {
+	board   sim.View
+	blocked map[sim.Point]bool // walls and snakes
+}
+
+func newGrid(board sim.View) *grid {
+	g := &grid{
+		board:   board,
+		blocked: make(map[sim.Point]bool),
+	}
+	for _, s := range board.Snakes() {
+		if !s.Alive() {
+			continue
+		}
+		for _, p := range s.Body() {
+			g.blocked[p] = true
+		}
+	}
+	return g
+}
+
+// next returns the tile next to p in the given direction, false when it is
+// off the board
+func (g *grid) next(p sim.Point, dir sim.Direction) (sim.Point, bool) {
+	p = p.Add(dir, 1)
+	rows, cols := g.board.Rows(), g.board.Cols()
+	if g.board.Walls() == sim.WrapWalls {
+		p.X = (p.X + cols) % cols
+		p.Y = (p.Y + rows) % rows
+	}
+	return p, p.X >= 0 && p.Y >= 0 && p.X < cols && p.Y < rows
+}
+
+// free reports whether the snake can move onto the tile
+func (g *grid) free(p sim.Point) bool {
+	return !g.blocked[p] && !g.board.Obstacle(p)
+}
+
+// safeMoves returns the directions the snake can move in without dying
+// right away, starting with the one it is going in
+func (g *grid) safeMoves(s *sim.Snake) []sim.Direction {
+	order := []sim.Direction{s.Direction()}
+	for _, dir := range directions {
+		if dir != s.Direction() && dir != s.Direction().Opposite() {
+			order = append(order, dir)
+		}
+	}
+
+	var moves []sim.Direction
+	for _, dir := range order {
+		if p, ok := g.next(s.Head(), dir); ok && g.free(p) {
+			moves = append(moves, dir)
+		}
+	}
+	return moves
+}
+
+// distance returns the number of moves between two tiles on an empty board
+func (g *grid) distance(a, b sim.Point) int {
+	dx, dy := abs(a.X-b.X), abs(a.Y-b.Y)
+	if g.board.Walls() == sim.WrapWalls {
+		dx = min(dx, g.board.Cols()-dx)
+		dy = min(dy, g.board.Rows()-dy)
+	}
+	return dx + dy
+}
+
+// space returns the number of free tiles reachable from p, stopping once
+// limit tiles were found
+func (g *grid) space(p sim.Point, limit int) int {
+	seen := map[sim.Point]bool{p: true}
+	queue := []sim.Point{p}
+	for len(queue) > 0 && len(seen) < limit {
+		curr := queue[0]
+		queue = queue[1:]
+		for _, dir := range directions {
+			n, ok := g.next(curr, dir)
+			if ok && !seen[n] && g.free(n) {
+				seen[n] = true
+				queue = append(queue, n)
+			}
+		}
+	}
+	return len(seen)
+}
+
+// path returns the first move of a shortest path from the head of the snake
+// to the target, false when the target cannot be reached
+func (g *grid) path(s *sim.Snake, target sim.Point) (sim.Direction, bool) {
+	head := s.Head()
+	first := map[sim.Point]sim.Direction{}
+	var queue []sim.Point
+	for _, dir := range g.safeMoves(s) {
+		p, _ := g.next(head, dir)
+		first[p] = dir
+		queue = append(queue, p)
+	}
+
+	for len(queue) > 0 {
+		curr := queue[0]
+		queue = queue[1:]
+		if curr == target {
+			return first[curr], true
+		}
+		for _, dir := range directions {
+			n, ok := g.next(curr, dir)
+			if _, seen := first[n]; ok && !seen && n != head && g.free(n) {
+				first[n] = first[curr]
+				queue = append(queue, n)
+			}
+		}
+	}
+	return 0, false
+}
+
+// dangerous reports whether the head of a snake at least as long as s could
+// move onto p on the next move
+func (g *grid) dangerous(s *sim.Snake, p sim.Point) bool {
+	for _, other := range g.board.Snakes() {
+		if other == s || !other.Alive() || len(other.Body()) < len(s.Body()) {
+			continue
+		}
+		if g.distance(other.Head(), p) == 1 {
+			return true
+		}
+	}
+	return false
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
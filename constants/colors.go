@@ -4,4 +4,6 @@ import "image/color"
 
 var (
 	LightBlue = color.RGBA{R: 51, G: 153, B: 218, A: 255}
+	WallBrown = color.RGBA{R: 92, G: 64, B: 51, A: 255}
+	Red       = color.RGBA{R: 220, G: 50, B: 47, A: 255}
 )
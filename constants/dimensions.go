@@ -4,5 +4,5 @@ package constants
 const (
 	ScreenWidth  = 640
 	ScreenHeight = 640
-	TileSize = 32
+	TileSize     = 32
 )
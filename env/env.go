@@ -0,0 +1,134 @@
+// Package env runs the game headless as an environment for training agents,
+// one move of the snake per step
+package env
+
+import (
+	"errors"
+
+	"github.com/adan-ea/GoSnakeGo/sim"
+)
+
+// Values of the tiles of an observation's grid
+const (
+	TileEmpty = iota
+	TileWall
+	TileBody
+	TileHead
+	TileFood
+)
+
+// Rewards given for what happened during a step, eating also gives the
+// points of the food
+const (
+	rewardDeath = -1
+	rewardWin   = 1
+)
+
+// Observation is what the agent sees of the board
+type Observation struct {
+	Rows      int       `json:"rows"`
+	Cols      int       `json:"cols"`
+	Grid      [][]int   `json:"grid"` // Grid[y][x] is one of the Tile values
+	Head      sim.Point `json:"head"`
+	Direction string    `json:"direction"`
+	Food      sim.Point `json:"food"`
+}
+
+// Info holds details about a step that are not part of the observation
+type Info struct {
+	Cause  string `json:"cause,omitempty"` // what killed the snake, empty while it is alive
+	Won    bool   `json:"won,omitempty"`   // the snake filled the board
+	Score  int    `json:"score"`
+	Length int    `json:"length"`
+	Steps  int    `json:"steps"`
+}
+
+// Env is a single snake game driven one move at a time
+type Env struct {
+	board *sim.Board
+	steps int
+}
+
+// New creates an environment, Reset must be called before the first step
+func New() *Env {
+	return &Env{}
+}
+
+// Reset starts a new game on a board created from cfg and returns its first
+// observation
+func (e *Env) Reset(cfg sim.Config) Observation {
+	e.board = sim.NewBoard(cfg)
+	e.steps = 0
+	return e.observe()
+}
+
+// Step turns the snake toward the given direction, or keeps it going when
+// turn is false, then moves it by one tile. A turn back onto the snake's own
+// neck is ignored.
+func (e *Env) Step(turn bool, dir sim.Direction) (Observation, float64, bool, Info, error) {
+	if e.board == nil {
+		return Observation{}, 0, false, Info{}, errors.New("the game has not been reset")
+	}
+	if e.board.GameOver() {
+		return Observation{}, 0, true, Info{}, errors.New("the game is over, reset it")
+	}
+
+	score := e.board.Score()
+	events := e.board.Step(sim.Action{Turn: turn, Dir: dir})
+	e.steps++
+
+	reward := float64(e.board.Score() - score)
+	info := Info{
+		Score:  e.board.Score(),
+		Length: len(e.board.Snake().Body()),
+		Steps:  e.steps,
+	}
+	for _, ev := range events {
+		switch ev.Kind {
+		case sim.EventDied:
+			reward += rewardDeath
+			info.Cause = ev.Cause.String()
+		case sim.EventWon:
+			reward += rewardWin
+			info.Won = true
+		}
+	}
+
+	return e.observe(), reward, e.board.GameOver(), info, nil
+}
+
+// observe returns what the agent sees of the board
+func (e *Env) observe() Observation {
+	b := e.board
+	grid := make([][]int, b.Rows())
+	for y := range grid {
+		grid[y] = make([]int, b.Cols())
+		for x := range grid[y] {
+			if b.Obstacle(sim.Point{X: x, Y: y}) {
+				grid[y][x] = TileWall
+			}
+		}
+	}
+
+	snake := b.Snake()
+	set := func(p sim.Point, tile int) {
+		if b.InBounds(p) {
+			grid[p.Y][p.X] = tile
+		}
+	}
+	food := b.Food().Pos()
+	set(food, TileFood)
+	for _, p := range snake.Body() {
+		set(p, TileBody)
+	}
+	set(snake.Head(), TileHead)
+
+	return Observation{
+		Rows:      b.Rows(),
+		Cols:      b.Cols(),
+		Grid:      grid,
+		Head:      snake.Head(),
+		Direction: snake.Direction().String(),
+		Food:      food,
+	}
+}
@@ -0,0 +1,124 @@
+package env_test
+
+import (
+	"bufio"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/adan-ea/GoSnakeGo/env"
+	"github.com/adan-ea/GoSnakeGo/sim"
+)
+
+func TestDeathAtTheWall(t *testing.T) {
+	e := env.New()
+	obs := e.Reset(sim.Config{Size: sim.Small, Seed: 1})
+
+	// going straight, the snake leaves the board once its head is past the
+	// last column
+	moves := obs.Cols - obs.Head.X
+	for step := 1; step <= moves; step++ {
+		_, reward, done, info, err := e.Step(false, 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if step < moves {
+			if done || reward < 0 || info.Cause != "" {
+				t.Fatalf("step %d: reward %v, done %v, cause %q before the wall", step, reward, done, info.Cause)
+			}
+			continue
+		}
+		if !done || reward != -1 || info.Cause != sim.CauseWall.String() {
+			t.Errorf("reward %v, done %v, cause %q at the wall, want -1, true, %q", reward, done, info.Cause, sim.CauseWall)
+		}
+	}
+
+	if _, _, done, _, err := e.Step(false, 0); !done || err == nil {
+		t.Error("stepped in a game that is over")
+	}
+}
+
+func TestRewardOfFood(t *testing.T) {
+	e := env.New()
+	obs := e.Reset(sim.Config{Size: sim.Small, Seed: 3})
+	if obs.Food.Y == obs.Head.Y {
+		t.Fatal("the food is on the row of the snake, pick another seed")
+	}
+
+	// reach the row of the food, then its column
+	for range obs.Rows + obs.Cols {
+		dir := sim.Down
+		switch {
+		case obs.Head.Y > obs.Food.Y:
+			dir = sim.Up
+		case obs.Head.Y == obs.Food.Y && obs.Head.X < obs.Food.X:
+			dir = sim.Right
+		case obs.Head.Y == obs.Food.Y:
+			dir = sim.Left
+		}
+
+		var reward float64
+		var done bool
+		var info env.Info
+		var err error
+		obs, reward, done, info, err = e.Step(true, dir)
+		if err != nil || done {
+			t.Fatalf("the snake died of %q on its way to the food: %v", info.Cause, err)
+		}
+		if info.Score > 0 {
+			if reward != float64(info.Score) {
+				t.Errorf("reward %v for eating, want the %d points of the food", reward, info.Score)
+			}
+			return
+		}
+		if reward != 0 {
+			t.Fatalf("reward %v without eating", reward)
+		}
+	}
+	t.Fatal("the snake never reached the food")
+}
+
+func TestServe(t *testing.T) {
+	requests := strings.Join([]string{
+		`{"cmd":"step"}`,
+		`{"cmd":"reset","seed":1,"size":"10x8"}`,
+		`{"cmd":"step","action":"down"}`,
+		`{"cmd":"step","action":"north"}`,
+		`not json`,
+		`{"cmd":"jump"}`,
+	}, "\n")
+	var out strings.Builder
+	if err := env.Serve(strings.NewReader(requests), &out); err != nil {
+		t.Fatal(err)
+	}
+
+	type response struct {
+		Obs   *env.Observation `json:"obs"`
+		Info  *env.Info        `json:"info"`
+		Error string           `json:"error"`
+	}
+	var responses []response
+	scanner := bufio.NewScanner(strings.NewReader(out.String()))
+	for scanner.Scan() {
+		var res response
+		if err := json.Unmarshal(scanner.Bytes(), &res); err != nil {
+			t.Fatal(err)
+		}
+		responses = append(responses, res)
+	}
+	if len(responses) != 6 {
+		t.Fatalf("%d responses to 6 requests", len(responses))
+	}
+
+	for _, i := range []int{0, 3, 4, 5} {
+		if responses[i].Error == "" {
+			t.Errorf("response %d has no error", i)
+		}
+	}
+	if obs := responses[1].Obs; obs == nil || obs.Cols != 10 || obs.Rows != 8 {
+		t.Errorf("reset to %+v, want a 10x8 board", obs)
+	}
+	if res := responses[2]; res.Obs == nil || res.Obs.Direction != "down" || res.Info.Steps != 1 {
+		t.Errorf("step answered %+v, want the snake going down after one step", res)
+	}
+}
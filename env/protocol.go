@@ -0,0 +1,94 @@
+package env
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/adan-ea/GoSnakeGo/sim"
+)
+
+// Longest request line accepted
+const maxLine = 1 << 20
+
+// request is one line sent by the agent
+type request struct {
+	Cmd    string  `json:"cmd"`    // "reset" or "step"
+	Seed   *uint64 `json:"seed"`   // seed of the game to reset, random when missing
+	Size   string  `json:"size"`   // size of the game to reset, small when empty, see sim.Config.SetSize
+	Action string  `json:"action"` // direction to turn to, empty to keep going
+}
+
+// response is the line answered to a request
+type response struct {
+	Obs    *Observation `json:"obs,omitempty"`
+	Reward float64      `json:"reward"`
+	Done   bool         `json:"done"`
+	Info   *Info        `json:"info,omitempty"`
+	Error  string       `json:"error,omitempty"`
+}
+
+// Serve reads one JSON request per line from r and writes one JSON response
+// per line to w until r is exhausted. Invalid requests get a response with
+// an error and do not stop the environment.
+func Serve(r io.Reader, w io.Writer) error {
+	e := New()
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(nil, maxLine)
+	bw := bufio.NewWriter(w)
+	enc := json.NewEncoder(bw)
+
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		if err := enc.Encode(e.handle(line)); err != nil {
+			return err
+		}
+		// the agent waits for the answer before sending the next request
+		if err := bw.Flush(); err != nil {
+			return err
+		}
+	}
+	return scanner.Err()
+}
+
+// handle runs a request and returns its response
+func (e *Env) handle(line string) response {
+	var req request
+	if err := json.Unmarshal([]byte(line), &req); err != nil {
+		return response{Error: fmt.Sprintf("invalid request: %v", err)}
+	}
+
+	switch req.Cmd {
+	case "reset":
+		cfg := sim.Config{Size: sim.Small, Seed: sim.NewSeed()}
+		if req.Size != "" {
+			if err := cfg.SetSize(req.Size); err != nil {
+				return response{Error: err.Error()}
+			}
+		}
+		if req.Seed != nil {
+			cfg.Seed = *req.Seed
+		}
+		obs := e.Reset(cfg)
+		return response{Obs: &obs, Info: &Info{Length: len(e.board.Snake().Body())}}
+
+	case "step":
+		turn := req.Action != ""
+		dir, err := sim.ParseDirection(req.Action)
+		if turn && err != nil {
+			return response{Error: err.Error()}
+		}
+		obs, reward, done, info, err := e.Step(turn, dir)
+		if err != nil {
+			return response{Done: done, Error: err.Error()}
+		}
+		return response{Obs: &obs, Reward: reward, Done: done, Info: &info}
+	}
+
+	return response{Error: fmt.Sprintf("unknown command %q", req.Cmd)}
+}
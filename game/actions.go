@@ -0,0 +1,233 @@
+package game
+
+import (
+	"encoding/json"
+	"log"
+	"slices"
+	"strings"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// Action is something the player does by pressing a key, the keys of every
+// action can be changed on the controls screen
+type Action int
+
+const (
+	// directions of player 1, who plays solo games with the keys of both players
+	ActionUp Action = iota
+	ActionDown
+	ActionLeft
+	ActionRight
+	// directions of player 2 in versus, they also resize custom boards
+	ActionUp2
+	ActionDown2
+	ActionLeft2
+	ActionRight2
+	ActionConfirm  // start a game, choose a menu item, play again
+	ActionContinue // continue the saved game, also chooses a menu item
+	ActionBack
+	ActionPause
+	// cycle the options of the title screen
+	ActionSize
+	ActionColor
+	ActionWalls
+	ActionLevel
+	ActionPowerUps
+	ActionMode
+	ActionRivals
+	ActionDifficulty
+	ActionCampaign
+	ActionWatch
+	ActionSettings
+	ActionReplay   // watch the replay of the game that just ended
+	ActionStep     // step one tick of a paused replay
+	ActionStrategy // switch the strategy of the AI being watched
+	NbActions
+)
+
+// screen is a set of screens reading actions
+type screen uint8
+
+const (
+	screenTitle screen = 1 << iota
+	screenPlay
+	screenMenu
+	screenGameOver
+	screenReplay
+	screenDemo
+)
+
+// actionInfo describes an action: its name in the settings file, its label
+// on the controls screen, its default keys and the screens
+// reading it. Two actions read by the same screen can't share a key.
+type actionInfo struct {
+	name    string
+	label   string
+	keys    []ebiten.Key
+	screens screen
+}
+
+var actions = [NbActions]actionInfo{
+	ActionUp:         {"up1", "P1 Up", []ebiten.Key{ebiten.KeyW}, screenPlay | screenMenu | screenReplay},
+	ActionDown:       {"down1", "P1 Down", []ebiten.Key{ebiten.KeyS}, screenPlay | screenMenu | screenReplay},
+	ActionLeft:       {"left1", "P1 Left", []ebiten.Key{ebiten.KeyA}, screenPlay | screenMenu | screenReplay},
+	ActionRight:      {"right1", "P1 Right", []ebiten.Key{ebiten.KeyD}, screenPlay | screenMenu | screenReplay},
+	ActionUp2:        {"up2", "P2 Up", []ebiten.Key{ebiten.KeyArrowUp}, screenTitle | screenPlay | screenMenu | screenReplay},
+	ActionDown2:      {"down2", "P2 Down", []ebiten.Key{ebiten.KeyArrowDown}, screenTitle | screenPlay | screenMenu | screenReplay},
+	ActionLeft2:      {"left2", "P2 Left", []ebiten.Key{ebiten.KeyArrowLeft}, screenTitle | screenPlay | screenMenu | screenReplay},
+	ActionRight2:     {"right2", "P2 Right", []ebiten.Key{ebiten.KeyArrowRight}, screenTitle | screenPlay | screenMenu | screenReplay},
+	ActionConfirm:    {"confirm", "Confirm", []ebiten.Key{ebiten.KeySpace}, screenTitle | screenMenu | screenGameOver | screenReplay},
+	ActionContinue:   {"continue", "Continue", []ebiten.Key{ebiten.KeyEnter}, screenTitle | screenMenu},
+	ActionBack:       {"back", "Back", []ebiten.Key{ebiten.KeyEscape}, screenPlay | screenMenu | screenGameOver | screenReplay | screenDemo},
+	ActionPause:      {"pause", "Pause", []ebiten.Key{ebiten.KeyP}, screenPlay | screenMenu},
+	ActionSize:       {"size", "Size", []ebiten.Key{ebiten.KeyS}, screenTitle},
+	ActionColor:      {"color", "Color", []ebiten.Key{ebiten.KeyC}, screenTitle},
+	ActionWalls:      {"walls", "Walls", []ebiten.Key{ebiten.KeyB}, screenTitle},
+	ActionLevel:      {"level", "Level", []ebiten.Key{ebiten.KeyL}, screenTitle},
+	ActionPowerUps:   {"powerUps", "Power-ups", []ebiten.Key{ebiten.KeyU}, screenTitle},
+	ActionMode:       {"mode", "Mode", []ebiten.Key{ebiten.KeyM}, screenTitle},
+	ActionRivals:     {"rivals", "Rivals", []ebiten.Key{ebiten.KeyI}, screenTitle},
+	ActionDifficulty: {"difficulty", "Difficulty", []ebiten.Key{ebiten.KeyK}, screenTitle},
+	ActionCampaign:   {"campaign", "Campaign", []ebiten.Key{ebiten.KeyG}, screenTitle},
+	ActionWatch:      {"watch", "Watch the AI", []ebiten.Key{ebiten.KeyV}, screenTitle},
+	ActionSettings:   {"settings", "Settings", []ebiten.Key{ebiten.KeyO}, screenTitle},
+	ActionReplay:     {"replay", "Replay", []ebiten.Key{ebiten.KeyR}, screenGameOver},
+	ActionStep:       {"step", "Replay step", []ebiten.Key{ebiten.KeyN}, screenReplay},
+	ActionStrategy:   {"strategy", "AI strategy", []ebiten.Key{ebiten.KeyH}, screenDemo},
+}
+
+func (a Action) String() string {
+	return actions[a].label
+}
+
+// Layout is how keys are named on the screens. Keys are physical keys named
+// after a US keyboard, so the keys where WASD is on QWERTY are ZQSD on AZERTY
+// and ,AOE on Dvorak whatever the layout.
+type Layout int
+
+const (
+	LayoutAuto Layout = iota // the names of the system's layout
+	LayoutQWERTY
+	LayoutAZERTY
+	LayoutDvorak
+	NbLayouts
+)
+
+func (l Layout) String() string {
+	switch l {
+	case LayoutQWERTY:
+		return "QWERTY"
+	case LayoutAZERTY:
+		return "AZERTY"
+	case LayoutDvorak:
+		return "Dvorak"
+	}
+	return "Auto"
+}
+
+// layoutNames are the names of the keys of a layout that are not named as on
+// a US keyboard
+var layoutNames = map[Layout]map[ebiten.Key]string{
+	LayoutAZERTY: {
+		ebiten.KeyQ: "A", ebiten.KeyW: "Z", ebiten.KeyA: "Q", ebiten.KeyZ: "W",
+		ebiten.KeySemicolon: "M", ebiten.KeyM: ",", ebiten.KeyComma: ";",
+		ebiten.KeyPeriod: ":", ebiten.KeySlash: "!",
+	},
+	LayoutDvorak: {
+		ebiten.KeyQ: "'", ebiten.KeyW: ",", ebiten.KeyE: ".", ebiten.KeyR: "P",
+		ebiten.KeyT: "Y", ebiten.KeyY: "F", ebiten.KeyU: "G", ebiten.KeyI: "C",
+		ebiten.KeyO: "R", ebiten.KeyP: "L", ebiten.KeyS: "O", ebiten.KeyD: "E",
+		ebiten.KeyF: "U", ebiten.KeyG: "I", ebiten.KeyH: "D", ebiten.KeyJ: "H",
+		ebiten.KeyK: "T", ebiten.KeyL: "N", ebiten.KeySemicolon: "S",
+		ebiten.KeyZ: ";", ebiten.KeyX: "Q", ebiten.KeyC: "J", ebiten.KeyV: "K",
+		ebiten.KeyB: "X", ebiten.KeyN: "B", ebiten.KeyComma: "W",
+		ebiten.KeyPeriod: "V", ebiten.KeySlash: "Z", ebiten.KeyQuote: "-",
+		ebiten.KeyMinus: "[", ebiten.KeyEqual: "]", ebiten.KeyBracketLeft: "/",
+		ebiten.KeyBracketRight: "=",
+	},
+}
+
+// keyName returns the name of the key on the layout, Auto falls back to the
+// US name when the system can't name it
+func (l Layout) keyName(key ebiten.Key) string {
+	if l == LayoutAuto {
+		if name := ebiten.KeyName(key); name != "" {
+			return strings.ToUpper(name)
+		}
+	}
+	if name, ok := layoutNames[l][key]; ok {
+		return name
+	}
+	return key.String()
+}
+
+// Bindings are the keys bound to each action, keyed by the name of the
+// action so the names of a newer version are kept
+type Bindings map[string][]ebiten.Key
+
+// defaultBindings returns the keys of every action on a US keyboard
+func defaultBindings() Bindings {
+	b := Bindings{}
+	for _, info := range actions {
+		b[info.name] = slices.Clone(info.keys)
+	}
+	return b
+}
+
+// keys returns the keys bound to the action
+func (b Bindings) keys(a Action) []ebiten.Key {
+	return b[actions[a].name]
+}
+
+// set binds the action to a single key
+func (b Bindings) set(a Action, key ebiten.Key) {
+	b[actions[a].name] = []ebiten.Key{key}
+}
+
+// conflict returns the other action a key is bound to on a screen reading
+// the given action
+func (b Bindings) conflict(a Action, key ebiten.Key) (Action, bool) {
+	for other := range actions {
+		if Action(other) != a && a.sharesScreen(Action(other)) &&
+			slices.Contains(b.keys(Action(other)), key) {
+			return Action(other), true
+		}
+	}
+	return 0, false
+}
+
+// sharesScreen reports whether a screen reads both actions, which then can't
+// share a key or a gamepad button
+func (a Action) sharesScreen(other Action) bool {
+	return actions[a].screens&actions[other].screens != 0
+}
+
+// reset binds every action to its default keys
+func (b Bindings) reset() {
+	for name, keys := range defaultBindings() {
+		b[name] = keys
+	}
+}
+
+// UnmarshalJSON reads the bindings by key name, skipping the names it does
+// not know so one bad key does not lose the others
+func (b *Bindings) UnmarshalJSON(data []byte) error {
+	var names map[string][]string
+	if err := json.Unmarshal(data, &names); err != nil {
+		return err
+	}
+
+	*b = Bindings{}
+	for action, keys := range names {
+		for _, name := range keys {
+			var key ebiten.Key
+			if err := key.UnmarshalText([]byte(name)); err != nil {
+				log.Println(err)
+				continue
+			}
+			(*b)[action] = append((*b)[action], key)
+		}
+	}
+	return nil
+}
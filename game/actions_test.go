@@ -0,0 +1,72 @@
+package game
+
+import (
+	"encoding/json"
+	"slices"
+	"testing"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+func TestBindingsUnmarshal(t *testing.T) {
+	var b Bindings
+	data := `{"up1": ["ArrowUp", "NoSuchKey", "W"], "dash": ["Q"]}`
+	if err := json.Unmarshal([]byte(data), &b); err != nil {
+		t.Fatal(err)
+	}
+
+	if keys := b.keys(ActionUp); !slices.Equal(keys, []ebiten.Key{ebiten.KeyArrowUp, ebiten.KeyW}) {
+		t.Errorf("up1 bound to %v, want ArrowUp and W", keys)
+	}
+	if keys := b["dash"]; !slices.Equal(keys, []ebiten.Key{ebiten.KeyQ}) {
+		t.Errorf("unknown action bound to %v, want Q", keys)
+	}
+
+	out, err := json.Marshal(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var again Bindings
+	if err := json.Unmarshal(out, &again); err != nil {
+		t.Fatal(err)
+	}
+	if len(again) != len(b) || !slices.Equal(again.keys(ActionUp), b.keys(ActionUp)) {
+		t.Errorf("bindings written as %s read back as %v", out, again)
+	}
+}
+
+func TestDefaultBindingsDoNotConflict(t *testing.T) {
+	b := defaultBindings()
+	for a := range NbActions {
+		for _, key := range b.keys(a) {
+			if other, ok := b.conflict(a, key); ok {
+				t.Errorf("%v and %v share %v", a, other, key)
+			}
+		}
+	}
+}
+
+func TestConflict(t *testing.T) {
+	tests := []struct {
+		name     string
+		action   Action
+		key      ebiten.Key
+		conflict Action
+		ok       bool
+	}{
+		{"same screen", ActionPause, ebiten.KeyW, ActionUp, true},
+		{"title and player 2", ActionSize, ebiten.KeyArrowUp, ActionUp2, true},
+		{"other screens", ActionSize, ebiten.KeyW, 0, false},
+		{"own key", ActionUp, ebiten.KeyW, 0, false},
+		{"free key", ActionPause, ebiten.KeyF5, 0, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			other, ok := defaultBindings().conflict(tt.action, tt.key)
+			if ok != tt.ok || other != tt.conflict {
+				t.Errorf("conflict %v %v, want %v %v", other, ok, tt.conflict, tt.ok)
+			}
+		})
+	}
+}
@@ -1,156 +1,172 @@
 package game
 
 import (
+	"fmt"
+	"image"
 	"image/color"
-	"math/rand"
-	"time"
+	"log"
 
+	"github.com/adan-ea/GoSnakeGo/bot"
 	"github.com/adan-ea/GoSnakeGo/constants"
+	"github.com/adan-ea/GoSnakeGo/replay"
 	"github.com/adan-ea/GoSnakeGo/resources/audio"
+	"github.com/adan-ea/GoSnakeGo/resources/fonts"
 	"github.com/adan-ea/GoSnakeGo/resources/images"
+	"github.com/adan-ea/GoSnakeGo/scoreboard"
+	"github.com/adan-ea/GoSnakeGo/sim"
 	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/text"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+	"golang.org/x/image/font"
 )
 
+// Board drives a simulated board with the keyboard and renders it
 type Board struct {
-	rows      int
-	cols      int
-	food      *Food
-	snake     *Snake
-	score     int
+	state     *sim.Board
+	sprite    *snakeSprite
 	highScore int
-	gameOver  bool
-	timer     time.Time
+	table     string // scoreboard table of the board, empty to keep its scores out of the scoreboard
+	replay    *replay.Replay
+	// controllers[i] drives the i-th snake
+	controllers []sim.Controller
+	difficulty  bot.Difficulty // how the rival snakes play
+	demo        bool           // played by the autopilot, silent and without replay
+	field       *ebiten.Image  // the board and its walls at full size
 }
 
-func newBoard(size Size, color Color) *Board {
-	rows, cols := getGridSize(size)
-	realSize := getSizeFromRowsCols(rows, cols)
+func newBoard(cfg sim.Config) *Board {
+	state := sim.NewBoard(cfg)
 	game := &Board{
-		rows:      rows,
-		cols:      cols,
-		timer:     time.Now(),
-		highScore: getHighestScore(realSize),
-		snake:     newSnake(color),
+		state:     state,
+		sprite:    newSnakeSprite(),
+		highScore: scoreboard.Best(scoreboard.Table(state)),
+		table:     scoreboard.Table(state),
+		replay:    replay.New(cfg),
 	}
-	game.placeFood()
+	game.setControllers(bot.Greedy)
 
 	return game
 }
 
 func (b *Board) Update(input *Input) error {
-	if b.gameOver {
+	if b.state.GameOver() {
 		return nil
 	}
 
-	// snake goes faster when there are more points
-	interval := calculateInterval(b.score)
-	if newDir, ok := Dir(); ok {
-		b.snake.changeDirection(newDir)
-	}
-
-	if time.Since(b.timer) >= interval {
-		if err := b.moveSnake(); err != nil {
-			return err
-		}
-
-		b.timer = time.Now()
-	}
+	// the snake moves faster when there are more points, see sim.DefaultSpeed
+	actions, events := b.state.TickWith(b.controllers)
+	b.replay.Record(b.state.Ticks(), actions...)
+	b.handleEvents(events)
 
 	return nil
 }
 
-func (b *Board) moveSnake() error {
-	// remove tail first, add 1 in front
-	b.snake.move()
-	if b.snakeLeftBoard() || b.snake.headHitsBody() {
-		audio.PlayOnce(audio.HitPlayer)
-		b.gameOver = true
-		saveHighScore(b.score, getSizeFromRowsCols(b.rows, b.cols))
-		return nil
+// handleEvents plays the sounds and records the scores for what happened during a step
+func (b *Board) handleEvents(events []sim.Event) {
+	if b.demo {
+		return
 	}
 
-	if b.snake.headHits(b.food.x, b.food.y) {
-		// the snake grows on the next move
-		b.snake.justAte = true
-
-		b.placeFood()
-		b.updateScore()
+	for _, e := range events {
+		switch e.Kind {
+		case sim.EventAte:
+			audio.PlayOnce(foodSound(e.Food))
+			b.updateHighScore()
+		case sim.EventDied, sim.EventShieldBroke:
+			audio.PlayOnce(audio.HitPlayer)
+		case sim.EventPowerUp:
+			audio.PlayOnce(audio.PowerUpPlayer)
+		case sim.EventWon:
+			audio.PlayOnce(audio.EatPlayer)
+		}
 	}
 
-	return nil
-}
-
-func (b *Board) snakeLeftBoard() bool {
-	head := b.snake.Head()
-	return head.x > b.cols-1 || head.y > b.rows-1 || head.x < 0 || head.y < 0
-}
-
-func (b *Board) placeFood() {
-	var x, y int
-	validPosition := false
-
-	for !validPosition {
-		x = rand.Intn(b.rows)
-		y = rand.Intn(b.cols)
-
-		validPosition = true
-		for _, p := range b.snake.body {
-			if p.x == x && p.y == y {
-				validPosition = false
-				break
+	if len(events) > 0 && b.state.GameOver() {
+		if b.table != "" {
+			if err := scoreboard.Save(b.state.Score(), b.table); err != nil {
+				log.Println(err)
 			}
 		}
+		saveReplay(b.replay)
 	}
-
-	b.food = newFood(x, y)
-}
-
-const (
-	baseInterval  = time.Millisecond * 200
-	minInterval   = time.Millisecond * 50
-	speedIncrease = time.Millisecond * 5
-)
-
-func calculateInterval(score int) time.Duration {
-	// Calculate the new interval by decreasing it linearly with the score
-	newInterval := baseInterval - time.Duration(score)*speedIncrease
-
-	// Ensure the interval does not go below the minimum interval
-	if newInterval < minInterval {
-		return minInterval
-	}
-	return newInterval
 }
 
 func (b *Board) Draw(screen *ebiten.Image) {
 	// Fill the screen with the light blue color
 	screen.Fill(constants.LightBlue)
 
-	gameWidth := b.cols * constants.TileSize
-	gameHeight := b.rows * constants.TileSize
+	b.drawField(screen)
+
+	if versus(b.state) {
+		b.drawVersusScores(screen)
+		b.drawEffects(screen, b.state.Snakes()[0], 120, 7, effectSpacing)
+		b.drawEffects(screen, b.state.Snakes()[1], 460, 7, -effectSpacing)
+	} else {
+		b.drawScore(screen, b.state.Score(), 0, 7)
+		b.drawEffects(screen, b.state.Snake(), 120, 7, effectSpacing)
+		b.drawHighScore(screen, b.highScore, 550, 7)
+	}
+
+	if b.state.TimeAttack() != nil {
+		b.drawCountdown(screen)
+	}
 
-	// Calculate the offset to center the game area
-	offsetX := (constants.ScreenWidth - gameWidth) / 2
-	offsetY := (constants.ScreenHeight - gameHeight) / 2
+	if goal := b.state.Goal(); goal != nil {
+		goalText := fmt.Sprintf("%s: %d/%d", getGoalText(*goal), goal.Progress(b.state), goal.Target)
+		goalX := (constants.ScreenWidth - font.MeasureString(fonts.RegularFont, goalText).Round()) / 2
+		text.Draw(screen, goalText, fonts.RegularFont, goalX, 30, color.White)
+	}
+}
 
+// drawField draws the board, its walls and what is on it, scaled down when
+// the board does not fit on the screen
+func (b *Board) drawField(screen *ebiten.Image) {
+	rows, cols := b.state.Rows(), b.state.Cols()
+	gameWidth := cols * constants.TileSize
+	gameHeight := rows * constants.TileSize
 	wallThickness := constants.TileSize / 2
-	wallImage := ebiten.NewImage(gameWidth+constants.TileSize, gameHeight+constants.TileSize)
-	wallImage.Fill(color.White)
-	op := &ebiten.DrawImageOptions{}
 
-	op.GeoM.Translate(float64(offsetX-wallThickness), float64(offsetY-wallThickness))
-	screen.DrawImage(wallImage, op)
+	if b.field == nil {
+		b.field = ebiten.NewImage(gameWidth+2*wallThickness, gameHeight+2*wallThickness)
+	}
+	b.field.Fill(color.White)
 
-	for y := 0; y < b.rows/2; y++ {
-		for x := 0; x < b.cols/2; x++ {
+	// drawing on the inside of the walls cuts the background tiles that do
+	// not fit on boards with odd dimensions
+	inside := b.field.SubImage(image.Rect(wallThickness, wallThickness, wallThickness+gameWidth, wallThickness+gameHeight)).(*ebiten.Image)
+	op := &ebiten.DrawImageOptions{}
+	for y := 0; y < (rows+1)/2; y++ {
+		for x := 0; x < (cols+1)/2; x++ {
 			op.GeoM.Reset()
-			op.GeoM.Translate(float64(x*constants.TileSize*2+offsetX), float64(y*constants.TileSize*2+offsetY))
-			screen.DrawImage(images.BackgroundSprite, op)
+			op.GeoM.Translate(float64(x*constants.TileSize*2+wallThickness), float64(y*constants.TileSize*2+wallThickness))
+			inside.DrawImage(images.BackgroundSprite, op)
+		}
+	}
+
+	if level := b.state.Level(); level != nil {
+		for _, p := range level.Obstacles {
+			x := float32(wallThickness + p.X*constants.TileSize)
+			y := float32(wallThickness + p.Y*constants.TileSize)
+			vector.DrawFilledRect(inside, x, y, constants.TileSize, constants.TileSize, constants.WallBrown, false)
 		}
 	}
 
-	b.snake.Draw(screen, offsetX, offsetY)
-	b.food.Draw(screen, offsetX, offsetY)
-	b.drawScore(screen, b.score, 0, 7)
-	b.drawHighScore(screen, b.highScore, 550, 7)
+	for _, snake := range b.state.Snakes() {
+		if snake.Alive() {
+			b.sprite.Draw(inside, snake, wallThickness, wallThickness)
+		}
+	}
+	drawFood(inside, b.state.Food(), b.state.Ticks(), wallThickness, wallThickness)
+	if p := b.state.PowerUp(); p != nil {
+		drawPowerUp(inside, p, b.state.Ticks(), wallThickness, wallThickness)
+	}
+
+	// center the inside of the walls, which may go past the edges of the screen
+	scale := min(1, float64(constants.ScreenWidth)/float64(gameWidth), float64(constants.ScreenHeight)/float64(gameHeight))
+	op.GeoM.Reset()
+	op.GeoM.Translate(-float64(wallThickness), -float64(wallThickness))
+	op.GeoM.Scale(scale, scale)
+	op.GeoM.Translate((constants.ScreenWidth-float64(gameWidth)*scale)/2, (constants.ScreenHeight-float64(gameHeight)*scale)/2)
+	op.Filter = ebiten.FilterLinear
+	screen.DrawImage(b.field, op)
 }
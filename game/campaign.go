@@ -0,0 +1,212 @@
+package game
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"image/color"
+	"log"
+	"os"
+	"path/filepath"
+	"slices"
+
+	"github.com/adan-ea/GoSnakeGo/constants"
+	"github.com/adan-ea/GoSnakeGo/resources/fonts"
+	"github.com/adan-ea/GoSnakeGo/resources/levels"
+	"github.com/adan-ea/GoSnakeGo/sim"
+	"github.com/adan-ea/GoSnakeGo/storage"
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/text"
+	"golang.org/x/image/font"
+)
+
+const (
+	campaignFileName = "campaign.json"
+	campaignVersion  = 1
+)
+
+// stage is one board of the campaign
+type stage struct {
+	name  string
+	level string // name of a built-in level, empty for an empty board
+	size  sim.Size
+	walls sim.Walls
+	goal  sim.Goal
+	food  sim.FoodTable // only apples when empty
+}
+
+// Stages of the campaign in the order they are unlocked
+var campaign = []stage{
+	{name: "First bites", size: sim.Small, goal: sim.Goal{Kind: sim.GoalApples, Target: 5}},
+	{name: "Growing up", size: sim.Medium, goal: sim.Goal{Kind: sim.GoalLength, Target: 15}},
+	{name: "Among pillars", level: "Pillars", goal: sim.Goal{Kind: sim.GoalApples, Target: 10}},
+	{name: "Hold on", level: "Cross", goal: sim.Goal{Kind: sim.GoalSurvive, Target: 60}},
+	{name: "Gardening", level: "Garden", goal: sim.Goal{Kind: sim.GoalApples, Target: 15}, food: sim.ClassicFood},
+	{name: "Around the world", size: sim.Large, walls: sim.WrapWalls, goal: sim.Goal{Kind: sim.GoalLength, Target: 30}, food: sim.WrapFood},
+	{name: "Tunnel vision", level: "Tunnels", walls: sim.WrapWalls, goal: sim.Goal{Kind: sim.GoalApples, Target: 20}, food: sim.WrapFood},
+}
+
+// config returns the board config of the stage
+func (s stage) config(color sim.Color, seed uint64) sim.Config {
+	goal := s.goal
+	return sim.Config{
+		Size:  s.size,
+		Color: color,
+		Walls: s.walls,
+		Level: levels.ByName(s.level),
+		Goal:  &goal,
+		Food:  s.food,
+		Seed:  seed,
+	}
+}
+
+func getGoalText(goal sim.Goal) string {
+	switch goal.Kind {
+	case sim.GoalApples:
+		return fmt.Sprintf("Eat %d apples", goal.Target)
+	case sim.GoalLength:
+		return fmt.Sprintf("Reach length %d", goal.Target)
+	case sim.GoalSurvive:
+		return fmt.Sprintf("Survive %d seconds", goal.Target)
+	}
+	return ""
+}
+
+// campaignProgress is the content of the campaign file, stages are keyed
+// by name so they can be reordered
+type campaignProgress struct {
+	Version int            `json:"version"`
+	Cleared []string       `json:"cleared"`
+	Best    map[string]int `json:"best"`
+}
+
+func campaignPath() (string, error) {
+	dir, err := storage.Dir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, campaignFileName), nil
+}
+
+// loadCampaignProgress reads the campaign file, starting a new campaign if there is none
+func loadCampaignProgress() *campaignProgress {
+	progress := &campaignProgress{Version: campaignVersion, Best: map[string]int{}}
+
+	path, err := campaignPath()
+	if err != nil {
+		log.Println(err)
+		return progress
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if !errors.Is(err, os.ErrNotExist) {
+			log.Println(err)
+		}
+		return progress
+	}
+
+	if err := json.Unmarshal(data, progress); err != nil {
+		log.Println(err)
+	}
+	if progress.Best == nil {
+		progress.Best = map[string]int{}
+	}
+	return progress
+}
+
+func (p *campaignProgress) save() {
+	data, err := json.Marshal(p)
+	if err != nil {
+		log.Println(err)
+		return
+	}
+
+	path, err := campaignPath()
+	if err != nil {
+		log.Println(err)
+		return
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		log.Println(err)
+	}
+}
+
+// unlocked reports whether the stage at index i can be played
+func (p *campaignProgress) unlocked(i int) bool {
+	return i == 0 || slices.Contains(p.Cleared, campaign[i-1].name)
+}
+
+// record keeps the score of a finished stage and unlocks the next one when it was won
+func (p *campaignProgress) record(s stage, score int, won bool) {
+	if score > p.Best[s.name] {
+		p.Best[s.name] = score
+	}
+	if won && !slices.Contains(p.Cleared, s.name) {
+		p.Cleared = append(p.Cleared, s.name)
+	}
+	p.save()
+}
+
+// openStages shows the stage select screen
+func (g *Game) openStages() {
+	g.progress = loadCampaignProgress()
+	g.stagesMenu = newMenu(g.stageItems()...)
+	if g.stage > 0 {
+		g.stagesMenu.selected = g.stage - 1
+	}
+	g.mode = ModeStages
+}
+
+func (g *Game) stageItems() []string {
+	items := make([]string, len(campaign))
+	for i, s := range campaign {
+		if !g.progress.unlocked(i) {
+			items[i] = fmt.Sprintf("%d. Locked", i+1)
+			continue
+		}
+		items[i] = fmt.Sprintf("%d. %s - %s", i+1, s.name, getGoalText(s.goal))
+		if best := g.progress.Best[s.name]; best > 0 {
+			items[i] += fmt.Sprintf(" (%d)", best)
+		}
+	}
+	return items
+}
+
+func (g *Game) updateStages() {
+	if Pressed(ActionBack) {
+		g.mode = ModeTitle
+		return
+	}
+
+	choice, ok := g.stagesMenu.Update()
+	if ok && g.progress.unlocked(choice) {
+		g.startStage(choice)
+	}
+}
+
+// startStage starts the stage at index i of the campaign
+func (g *Game) startStage(i int) {
+	g.stage = i + 1
+	g.board = g.newBoard()
+	g.mode = ModeGame
+}
+
+// stageBoard creates the board of the current stage. Campaign scores are
+// kept in the campaign file instead of the scoreboard.
+func (g *Game) stageBoard(seed uint64) *Board {
+	s := campaign[g.stage-1]
+	board := newBoard(s.config(g.color, seed))
+	board.table = ""
+	board.highScore = g.progress.Best[s.name]
+	return board
+}
+
+func (g *Game) drawStages(screen *ebiten.Image) {
+	title := "Campaign"
+	titleX := (constants.ScreenWidth - font.MeasureString(fonts.BigFont, title).Round()) / 2
+	titleY := 100
+	text.Draw(screen, title, fonts.BigFont, titleX, titleY, color.White)
+
+	g.stagesMenu.Draw(screen, titleY+80)
+}
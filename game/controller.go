@@ -0,0 +1,38 @@
+package game
+
+import (
+	"github.com/adan-ea/GoSnakeGo/bot"
+	"github.com/adan-ea/GoSnakeGo/sim"
+)
+
+// setControllers gives a controller to each snake: the keys and gamepads of
+// both players in a solo game, the ones of player 1 and player 2 in versus,
+// and a bot of the given difficulty to each rival snake
+func (b *Board) setControllers(difficulty bot.Difficulty) {
+	b.difficulty = difficulty
+	b.controllers = make([]sim.Controller, len(b.state.Snakes()))
+	for i, s := range b.state.Snakes() {
+		switch {
+		case s.Bot():
+			b.controllers[i] = bot.New(difficulty)
+		case versus(b.state) && i == 0:
+			b.controllers[i] = human{DirPlayer1}
+		case versus(b.state):
+			b.controllers[i] = human{DirPlayer2}
+		default:
+			b.controllers[i] = human{Dir}
+		}
+	}
+}
+
+// human drives a snake with the directions of a player's keys and gamepads
+type human struct {
+	dir func() (sim.Direction, bool) // direction pressed during this update, see Dir
+}
+
+func (h human) Action(sim.View, int) sim.Action {
+	if dir, ok := h.dir(); ok {
+		return sim.Action{Turn: true, Dir: dir}
+	}
+	return sim.Action{}
+}
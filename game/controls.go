@@ -0,0 +1,164 @@
+package game
+
+import (
+	"fmt"
+	"image/color"
+	"strings"
+
+	"github.com/adan-ea/GoSnakeGo/constants"
+	"github.com/adan-ea/GoSnakeGo/resources/fonts"
+	"github.com/adan-ea/GoSnakeGo/sim"
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+	"github.com/hajimehoshi/ebiten/v2/text"
+	"golang.org/x/image/font"
+)
+
+// Items of the controls menu after the one of each action
+const (
+	controlsLayout = int(NbActions) + iota
+	controlsReset
+	controlsBack
+)
+
+// Actions shown at once on the controls screen
+const controlsRows = 8
+
+// controls is the screen where the keys of every action are changed
+type controls struct {
+	menu    *menu
+	waiting bool   // whether the next key pressed is bound to the selected action
+	message string // why the last key was refused
+}
+
+// openControls shows the controls, leaving them goes back to the settings
+func (g *Game) openControls() {
+	g.controls = &controls{menu: newMenu()}
+	g.controls.menu.rows = controlsRows
+	g.controls.menu.items = g.controls.items()
+	g.mode = ModeControls
+}
+
+func (g *Game) updateControls() {
+	c := g.controls
+	if c.waiting {
+		// only keys are bound, B on a gamepad cancels like the key of Back
+		keys := inpututil.AppendJustPressedKeys(nil)
+		switch {
+		case len(keys) > 0:
+			c.waiting = false
+			g.bind(Action(c.menu.selected), keys[0])
+		case pads.pressed(ActionBack):
+			c.waiting = false
+		}
+		c.menu.items = c.items()
+		return
+	}
+
+	if Pressed(ActionBack) {
+		g.mode = ModeSettings
+		return
+	}
+
+	if dir, ok := Dir(); ok && c.menu.selected == controlsLayout {
+		switch dir {
+		case sim.Left:
+			g.switchLayout(-1)
+		case sim.Right:
+			g.switchLayout(1)
+		}
+	}
+
+	if choice, ok := c.menu.Update(); ok {
+		c.message = ""
+		switch {
+		case choice < int(NbActions):
+			c.waiting = true
+		case choice == controlsLayout:
+			g.switchLayout(1)
+		case choice == controlsReset:
+			bindings.reset()
+			g.saveBindings()
+		case choice == controlsBack:
+			g.mode = ModeSettings
+		}
+	}
+	c.menu.items = c.items()
+}
+
+// bind binds the action to the key unless another action of the same
+// screens has it. The key of Back cancels.
+func (g *Game) bind(a Action, key ebiten.Key) {
+	if a != ActionBack && Pressed(ActionBack) {
+		return
+	}
+	if other, ok := bindings.conflict(a, key); ok {
+		g.controls.message = fmt.Sprintf("%s is already bound to %s", keyLayout.keyName(key), other)
+		return
+	}
+
+	bindings.set(a, key)
+	g.saveBindings()
+}
+
+// switchLayout names the keys after the layout delta layouts away, the keys
+// bound stay the same
+func (g *Game) switchLayout(delta int) {
+	keyLayout = (keyLayout + Layout(delta) + NbLayouts) % NbLayouts
+	g.settings.Layout = keyLayout
+	g.saveBindings()
+}
+
+// saveBindings saves the bindings and the layout changed on the controls screen
+func (g *Game) saveBindings() {
+	if g.opts.Settings != nil {
+		g.settings.save()
+	}
+}
+
+// items returns an item with the keys of each action followed by the layout
+func (c *controls) items() []string {
+	items := make([]string, 0, NbActions+2)
+	for a := range NbActions {
+		if c.waiting && int(a) == c.menu.selected {
+			items = append(items, a.String()+": press a key")
+			continue
+		}
+		names := make([]string, len(bindings.keys(a)))
+		for i, key := range bindings.keys(a) {
+			names[i] = keyLayout.keyName(key)
+			if _, ok := bindings.conflict(a, key); ok {
+				names[i] += " (conflict)"
+			}
+		}
+		items = append(items, a.String()+": "+strings.Join(names, ", "))
+	}
+
+	return append(items, "Layout: "+keyLayout.String(), "Reset keys", "Back")
+}
+
+func (g *Game) drawControls(screen *ebiten.Image) {
+	if g.settingsFrom == ModePause {
+		g.board.Draw(screen)
+		drawOverlay(screen)
+	}
+
+	c := g.controls
+	title := "Controls"
+	titleX := (constants.ScreenWidth - font.MeasureString(fonts.BigFont, title).Round()) / 2
+	titleY := (constants.ScreenHeight / 2) - 150
+	text.Draw(screen, title, fonts.BigFont, titleX, titleY, color.White)
+
+	c.menu.Draw(screen, titleY+80)
+
+	hint := "Left/Right on Layout to rename the keys"
+	clr := color.Color(color.White)
+	if c.waiting {
+		hint = keyText(ActionBack) + " to cancel"
+	}
+	if c.message != "" {
+		hint, clr = c.message, constants.Red
+	}
+	hintX := (constants.ScreenWidth - font.MeasureString(fonts.RegularFont, hint).Round()) / 2
+	text.Draw(screen, hint, fonts.RegularFont, hintX, constants.ScreenHeight-50, clr)
+}
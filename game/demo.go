@@ -0,0 +1,94 @@
+package game
+
+import (
+	"image/color"
+
+	"github.com/adan-ea/GoSnakeGo/bot"
+	"github.com/adan-ea/GoSnakeGo/constants"
+	"github.com/adan-ea/GoSnakeGo/resources/fonts"
+	"github.com/adan-ea/GoSnakeGo/sim"
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/text"
+	"golang.org/x/image/font"
+)
+
+const (
+	// Ticks without input on the title screen before the demo starts by itself
+	attractDelay = 20 * sim.TicksPerSecond
+	// Ticks a finished demo game stays on screen before the next one starts
+	demoRestartDelay = 2 * sim.TicksPerSecond
+)
+
+// demo shows the autopilot playing
+type demo struct {
+	board       *Board
+	attract     bool // started by itself from the title screen, any key stops it
+	hamiltonian bool // the autopilot follows a Hamiltonian cycle
+	over        int  // ticks since the game ended
+}
+
+// startDemo shows the autopilot playing with the options of the title screen
+func (g *Game) startDemo(attract bool) {
+	g.demo = &demo{attract: attract}
+	g.newDemoBoard()
+	g.mode = ModeDemo
+}
+
+// newDemoBoard starts a new game for the autopilot, its scores and replay
+// are not saved
+func (g *Game) newDemoBoard() {
+	board := newBoard(sim.Config{
+		Size:  g.size,
+		Rows:  g.rows,
+		Cols:  g.cols,
+		Color: g.color,
+		Walls: g.walls,
+		Level: g.chosenLevel(),
+		Food:  sim.FoodFor(g.walls),
+		Seed:  sim.NewSeed(),
+	})
+	board.table = ""
+	board.demo = true
+	board.controllers[0] = bot.NewAutopilot(g.demo.hamiltonian)
+
+	g.demo.board = board
+	g.demo.over = 0
+}
+
+func (g *Game) updateDemo() {
+	d := g.demo
+	if (d.attract && AnyKey()) || Pressed(ActionBack) {
+		g.idle = 0
+		g.mode = ModeTitle
+		return
+	}
+	if !d.attract && Pressed(ActionStrategy) {
+		d.hamiltonian = !d.hamiltonian
+		g.newDemoBoard()
+		return
+	}
+
+	if d.board.state.GameOver() {
+		d.over++
+		if d.over >= demoRestartDelay {
+			g.newDemoBoard()
+		}
+		return
+	}
+	d.board.Update(g.input)
+}
+
+func (g *Game) drawDemo(screen *ebiten.Image) {
+	g.demo.board.Draw(screen)
+
+	hint := "Press any key"
+	if !g.demo.attract {
+		strategy := "A*"
+		if g.demo.hamiltonian {
+			strategy = "Hamiltonian cycle"
+		}
+		hint = "Autopilot: " + strategy + "  " + keyText(ActionStrategy) + " to switch, " + keyText(ActionBack) + " to leave"
+	}
+	hintX := (constants.ScreenWidth - font.MeasureString(fonts.RegularFont, hint).Round()) / 2
+	text.Draw(screen, hint, fonts.RegularFont, hintX, constants.ScreenHeight-10, color.White)
+}
@@ -2,28 +2,42 @@ package game
 
 import (
 	"github.com/adan-ea/GoSnakeGo/constants"
+	"github.com/adan-ea/GoSnakeGo/resources/audio"
 	"github.com/adan-ea/GoSnakeGo/resources/images"
+	"github.com/adan-ea/GoSnakeGo/sim"
 	"github.com/hajimehoshi/ebiten/v2"
+	eaudio "github.com/hajimehoshi/ebiten/v2/audio"
 )
 
-// Food represents the food
-type Food struct {
-	x, y int
-}
+// Ticks before expiring during which timed food blinks
+const blinkTicks = sim.TicksPerSecond
 
-func newFood(x, y int) *Food {
-	return &Food{
-		x: x,
-		y: y,
+// foodSound returns the sound played when eating the given kind of food
+func foodSound(kind sim.FoodKind) *eaudio.Player {
+	switch kind {
+	case sim.GoldenApple:
+		return audio.GoldenPlayer
+	case sim.PoisonApple:
+		return audio.PoisonPlayer
+	case sim.BonusApple:
+		return audio.BonusPlayer
+	case sim.BigApple:
+		return audio.BigPlayer
 	}
+	return audio.EatPlayer
 }
 
-// Draw renders the food on the screen
-func (f *Food) Draw(screen *ebiten.Image, offsetX, offsetY int) {
-	sx := float64(offsetX + f.x*constants.TileSize)
-	sy := float64(offsetY + f.y*constants.TileSize)
+// drawFood renders the food on the screen, timed food blinks before it disappears
+func drawFood(screen *ebiten.Image, f *sim.Food, ticks int, offsetX, offsetY int) {
+	if left := f.Expires() - ticks; f.Expires() > 0 && left < blinkTicks && left/6%2 == 0 {
+		return
+	}
+
+	pos := f.Pos()
+	sx := float64(offsetX + pos.X*constants.TileSize)
+	sy := float64(offsetY + pos.Y*constants.TileSize)
 
 	op := &ebiten.DrawImageOptions{}
 	op.GeoM.Translate(sx, sy)
-	screen.DrawImage(images.FoodSprite, op)
+	screen.DrawImage(images.FoodKindSprite[int(f.Kind())], op)
 }
@@ -1,71 +1,359 @@
 package game
 
 import (
+	"fmt"
 	"image/color"
+	"log"
 	"strconv"
 
+	"github.com/adan-ea/GoSnakeGo/bot"
 	"github.com/adan-ea/GoSnakeGo/constants"
+	"github.com/adan-ea/GoSnakeGo/replay"
 	"github.com/adan-ea/GoSnakeGo/resources/audio"
 	"github.com/adan-ea/GoSnakeGo/resources/fonts"
 	"github.com/adan-ea/GoSnakeGo/resources/images"
+	"github.com/adan-ea/GoSnakeGo/resources/levels"
+	"github.com/adan-ea/GoSnakeGo/sim"
 	"github.com/hajimehoshi/ebiten/v2"
 	"github.com/hajimehoshi/ebiten/v2/text"
 	"golang.org/x/image/font"
 )
 
+// Dimensions of custom boards until the player resizes them
 const (
-	bestScorePath = "resources/scoreboard.txt"
-	nbScoreSaved  = 5
+	defaultCols = 30
+	defaultRows = 12
 )
 
-var sizeChanged bool
-var colorChanged bool
+// Options are the settings the game is launched with
+type Options struct {
+	Seed    uint64 // seed used for every game when HasSeed is set
+	HasSeed bool
+	Size    sim.Size       // size picked on the title screen
+	Rows    int            // rows of a sim.CustomSize board, defaultRows when zero
+	Cols    int            // columns of a sim.CustomSize board, defaultCols when zero
+	Color   sim.Color      // color picked on the title screen
+	Mode    PlayMode       // mode picked on the title screen
+	Replay  *replay.Replay // replay to watch instead of showing the title screen
+	// settings to start with and to save the player's changes to, the
+	// defaults are used and not saved when nil
+	Settings *Settings
+	// start in fullscreen and without music or sounds
+	Fullscreen bool
+	Mute       bool
+}
 
 // Game represents the game state and logic
 type Game struct {
-	input *Input
-	board *Board
-	size  Size
-	color Color
-	mode  Mode
+	input  *Input
+	board  *Board
+	viewer *replayViewer
+	size   sim.Size
+	rows   int // rows of a custom board
+	cols   int // columns of a custom board
+	color  sim.Color
+	walls  sim.Walls
+	// whether power-ups spawn on the board
+	powerUps bool
+	playMode PlayMode
+	// number of rival snakes played by the computer and how they play
+	rivals     int
+	difficulty bot.Difficulty
+	match      match // rounds won by each player in versus
+	level      int   // index of the chosen level in levels.Levels plus one, 0 for none
+	stage      int   // index of the campaign stage being played plus one, 0 outside of the campaign
+	mode       Mode
+	opts       Options
+	// whether there is a saved game to continue
+	canContinue  bool
+	focused      bool
+	pauseMenu    *menu
+	settingsMenu *menu
+	settingsFrom Mode // mode to go back to when leaving the settings
+	controls     *controls
+	gamepadsMenu *menu
+	stagesMenu   *menu
+	progress     *campaignProgress
+	demo         *demo
+	idle         int // ticks spent on the title screen without input
+	settings     *Settings
 }
 
-func NewGame() *Game {
+func NewGame(opts Options) *Game {
 	game := &Game{
-		input: newInput(),
+		input:       newInput(),
+		opts:        opts,
+		size:        opts.Size,
+		color:       opts.Color,
+		playMode:    opts.Mode,
+		rows:        defaultRows,
+		cols:        defaultCols,
+		canContinue: hasSavedGame(),
+		focused:     true,
+	}
+
+	if opts.Rows > 0 && opts.Cols > 0 {
+		game.rows, game.cols = sim.ClampSide(opts.Rows), sim.ClampSide(opts.Cols)
+	}
+
+	game.settings = opts.Settings
+	if game.settings == nil {
+		game.settings = DefaultSettings()
+	}
+	bindings = game.settings.Keys
+	keyLayout = game.settings.Layout
+
+	ebiten.SetFullscreen(opts.Fullscreen)
+	audio.MusicVolume = game.settings.MusicVolume
+	audio.SoundVolume = game.settings.SoundVolume
+	if opts.Mute {
+		audio.MusicVolume = 0
+		audio.SoundVolume = 0
+	}
+
+	if opts.Replay != nil {
+		game.viewer = newReplayViewer(opts.Replay)
+		game.mode = ModeReplay
 	}
 
 	return game
 }
 
+// newBoard starts a new board with the chosen options
+func (g *Game) newBoard() *Board {
+	seed := g.opts.Seed
+	if !g.opts.HasSeed {
+		seed = sim.NewSeed()
+	}
+
+	if g.stage > 0 {
+		return g.stageBoard(seed)
+	}
+
+	cfg := sim.Config{
+		Size:       g.size,
+		Rows:       g.rows,
+		Cols:       g.cols,
+		Color:      g.color,
+		Walls:      g.walls,
+		Level:      g.chosenLevel(),
+		Food:       sim.FoodFor(g.walls),
+		Power:      g.powerUps,
+		TimeAttack: timeAttack(g.playMode),
+		Seed:       seed,
+	}
+	if g.playMode == PlayVersus {
+		cfg.Snakes = versusSnakes(g.color)
+	}
+	if g.rivals > 0 {
+		if len(cfg.Snakes) == 0 {
+			cfg.Snakes = []sim.SnakeConfig{{Color: g.color}}
+		}
+		cfg.Snakes = append(cfg.Snakes, rivalSnakes(g.color, len(cfg.Snakes), g.rivals)...)
+	}
+
+	board := newBoard(cfg)
+	board.setControllers(g.difficulty)
+	return board
+}
+
+// chosenLevel returns the level picked on the title screen, nil for none
+func (g *Game) chosenLevel() *sim.Level {
+	if g.level == 0 {
+		return nil
+	}
+	return levels.Levels[g.level-1]
+}
+
+// playing reports whether a board is in progress, even if it is paused
+func (g *Game) playing() bool {
+	mode := g.mode
+	if mode == ModeSettings || mode == ModeControls || mode == ModeGamepads {
+		mode = g.settingsFrom
+	}
+	return (mode == ModeGame || mode == ModePause) && !g.board.state.GameOver()
+}
+
+// syncSettings saves the settings when the player changed one of them
+func (g *Game) syncSettings() {
+	if g.opts.Settings == nil {
+		return
+	}
+
+	c := g.settings.choices
+	c.Size, c.Rows, c.Cols = g.size, g.rows, g.cols
+	c.Color = g.color
+	c.Mode = g.playMode
+	// muting from the command line does not forget the volumes
+	if !g.opts.Mute || audio.MusicVolume > 0 || audio.SoundVolume > 0 {
+		c.MusicVolume, c.SoundVolume = audio.MusicVolume, audio.SoundVolume
+	}
+	c.Fullscreen = ebiten.IsFullscreen()
+	if w, h := ebiten.WindowSize(); !c.Fullscreen && w > 0 && h > 0 {
+		c.WindowWidth, c.WindowHeight = w, h
+	}
+
+	if c != g.settings.choices {
+		g.settings.choices = c
+		g.settings.save()
+	}
+}
+
+// autosave saves the game in progress so it can be continued from the title screen
+func (g *Game) autosave() {
+	if !g.playing() {
+		return
+	}
+
+	if err := saveGame(g.board, g.stage, g.match); err != nil {
+		log.Println(err)
+		return
+	}
+	g.canContinue = true
+}
+
 func (g *Game) Update() error {
+	if ebiten.IsWindowBeingClosed() {
+		g.autosave()
+		return ebiten.Termination
+	}
+	defer g.syncSettings()
+	pads.update()
+
+	// save and pause when the window loses focus
+	if !ebiten.IsFocused() {
+		if g.focused {
+			g.autosave()
+			if g.mode == ModeGame {
+				g.pause()
+			}
+			g.focused = false
+		}
+		return nil
+	}
+	g.focused = true
 
 	switch g.mode {
 	case ModeTitle:
+		// the demo starts by itself when nobody plays
+		g.idle++
+		if AnyKey() {
+			g.idle = 0
+		}
+		if g.idle >= attractDelay {
+			g.startDemo(true)
+			return nil
+		}
+		if Pressed(ActionWatch) {
+			g.startDemo(false)
+			return nil
+		}
+
 		handleSizeOption(g)
 		handleColorOption(g)
-		if Space() {
-			g.board = newBoard(g.size, g.color)
+		handleWallsOption(g)
+		handleLevelOption(g)
+		handlePowerUpsOption(g)
+		handlePlayModeOption(g)
+		handleRivalsOption(g)
+		if Pressed(ActionContinue) && g.canContinue {
+			board, stage, m, err := loadGame()
+			if err != nil {
+				log.Println(err)
+				g.canContinue = false
+				return nil
+			}
+			g.board = board
+			g.stage = stage
+			g.match = m
+			if stage > 0 {
+				g.progress = loadCampaignProgress()
+				g.board.table = ""
+				g.board.highScore = g.progress.Best[campaign[stage-1].name]
+			}
+			g.mode = ModeGame
+		}
+		if Pressed(ActionCampaign) {
+			g.openStages()
+		}
+		if Pressed(ActionSettings) {
+			g.openSettings(ModeTitle)
+			return nil
+		}
+		if Pressed(ActionConfirm) {
+			g.stage = 0
+			g.match = match{}
+			g.board = g.newBoard()
 			g.mode = ModeGame
 		}
 	case ModeGame:
-		if g.board.gameOver {
-			audio.PlayOnce(audio.GameOverPlayer)
+		if g.board.state.GameOver() {
+			if !g.board.state.Won() {
+				audio.PlayOnce(audio.GameOverPlayer)
+			}
+			if g.stage > 0 {
+				g.progress.record(campaign[g.stage-1], g.board.state.Score(), g.board.state.Won())
+			}
+			if versus(g.board.state) {
+				g.match.record(g.board.state)
+			}
+			deleteSavedGame()
+			g.canContinue = false
 			g.mode = ModeGameOver
+			return nil
+		}
+
+		if Pressed(ActionBack) || Pressed(ActionPause) {
+			g.pause()
+			return nil
 		}
 
 		audio.PlayLoop(audio.ThemePlayer)
 		g.board.Update(g.input)
+	case ModePause:
+		g.updatePause()
+	case ModeSettings:
+		g.updateSettings()
+	case ModeControls:
+		g.updateControls()
+	case ModeGamepads:
+		g.updateGamepads()
+	case ModeStages:
+		g.updateStages()
+	case ModeDemo:
+		g.updateDemo()
 
 	case ModeGameOver:
 		audio.ThemePlayer.Pause()
 
-		if Space() {
-			g.board = newBoard(g.size, g.color)
+		if Pressed(ActionConfirm) {
+			// a won stage moves on to the next one
+			if g.stage > 0 && g.board.state.Won() && g.stage < len(campaign) {
+				g.stage++
+			}
+			// a new match starts once a player has won this one
+			if g.match.winner() >= 0 {
+				g.match = match{}
+			}
+			g.board = g.newBoard()
 			g.mode = ModeGame
 		}
 
-		if Escape() {
+		if Pressed(ActionReplay) {
+			g.viewer = newReplayViewer(g.board.replay)
+			g.mode = ModeReplay
+		}
+
+		if Pressed(ActionBack) {
+			g.mode = ModeTitle
+			if g.stage > 0 {
+				g.openStages()
+			}
+		}
+	case ModeReplay:
+		g.viewer.Update()
+
+		if Pressed(ActionBack) {
 			g.mode = ModeTitle
 		}
 	}
@@ -81,7 +369,22 @@ func (g *Game) Draw(screen *ebiten.Image) {
 		g.board.Draw(screen)
 	case ModeGameOver:
 		g.DrawGameOver(screen)
+	case ModeReplay:
+		g.viewer.Draw(screen)
+	case ModePause:
+		g.drawPause(screen)
+	case ModeSettings:
+		g.drawSettings(screen)
+	case ModeControls:
+		g.drawControls(screen)
+	case ModeStages:
+		g.drawStages(screen)
+	case ModeDemo:
+		g.drawDemo(screen)
+	case ModeGamepads:
+		g.drawGamepads(screen)
 	}
+	pads.drawNotice(screen)
 }
 
 func (g *Game) Layout(outsideWidth, outsideHeight int) (int, int) {
@@ -89,28 +392,88 @@ func (g *Game) Layout(outsideWidth, outsideHeight int) (int, int) {
 }
 
 func handleColorOption(g *Game) {
-	if KeyC() && !colorChanged {
-		colorChanged = true
-		g.color = (g.color + 1) % nbColors
-	} else if !KeyC() {
-		colorChanged = false
+	if Pressed(ActionColor) {
+		g.color = (g.color + 1) % sim.NbColors
+	}
+}
+
+func handleWallsOption(g *Game) {
+	if Pressed(ActionWalls) {
+		g.walls = (g.walls + 1) % sim.NbWalls
+	}
+}
+
+func handleLevelOption(g *Game) {
+	if Pressed(ActionLevel) {
+		g.level = (g.level + 1) % (len(levels.Levels) + 1)
+	}
+}
+
+func handlePowerUpsOption(g *Game) {
+	if Pressed(ActionPowerUps) {
+		g.powerUps = !g.powerUps
+	}
+}
+
+func handlePlayModeOption(g *Game) {
+	if Pressed(ActionMode) {
+		g.playMode = (g.playMode + 1) % NbPlayModes
+	}
+}
+
+func handleRivalsOption(g *Game) {
+	if Pressed(ActionRivals) {
+		g.rivals = (g.rivals + 1) % (maxRivals + 1)
+	}
+	if Pressed(ActionDifficulty) {
+		g.difficulty = (g.difficulty + 1) % bot.NbDifficulties
 	}
 }
 
 func handleSizeOption(g *Game) {
-	if KeyS() && !sizeChanged {
-		sizeChanged = true
-		g.size = (g.size + 1) % nbSize
-	} else if !KeyS() {
-		sizeChanged = false
+	if Pressed(ActionSize) {
+		g.size = (g.size + 1) % sim.NbSize
+	}
+	if g.size != sim.CustomSize {
+		return
+	}
+
+	// player 2's keys resize custom boards, player 1's may pick options
+	dir, ok := DirPlayer2()
+	if !ok {
+		return
+	}
+	switch dir {
+	case sim.Left:
+		g.cols = sim.ClampSide(g.cols - 1)
+	case sim.Right:
+		g.cols = sim.ClampSide(g.cols + 1)
+	case sim.Up:
+		g.rows = sim.ClampSide(g.rows + 1)
+	case sim.Down:
+		g.rows = sim.ClampSide(g.rows - 1)
 	}
 }
 
 func (g *Game) DrawMainPage(screen *ebiten.Image) {
 	title := "Go Snake Go!"
-	sizeText := "Size: " + getSizeText(g.size)
+	sizeText := "Size: " + g.size.String()
+	if g.size == sim.CustomSize {
+		sizeText = fmt.Sprintf("Size: Custom %dx%d", g.cols, g.rows)
+	}
 	colorText := "Color: " + getColorText(g.color)
-	startText := "Space to start"
+	wallsText := "Walls: " + getWallsText(g.walls)
+	levelText := "Level: None"
+	if level := g.chosenLevel(); level != nil {
+		sizeText = fmt.Sprintf("Size: %dx%d", level.Cols, level.Rows)
+		levelText = "Level: " + level.Name
+	}
+	powerUpsText := "Power-ups: " + getOnOffText(g.powerUps)
+	playModeText := "Mode: " + getPlayModeText(g.playMode)
+	rivalsText := "Rivals: " + getRivalsText(g.rivals, g.difficulty)
+	startText := fmt.Sprintf("%s to start, %s for the settings", keyText(ActionConfirm), keyText(ActionSettings))
+	campaignText := fmt.Sprintf("%s for the campaign, %s to watch the AI", keyText(ActionCampaign), keyText(ActionWatch))
+	continueText := keyText(ActionContinue) + " to continue"
 
 	// Set the positions for the text
 	titleX := (constants.ScreenWidth - font.MeasureString(fonts.BigFont, title).Round()) / 2
@@ -120,16 +483,46 @@ func (g *Game) DrawMainPage(screen *ebiten.Image) {
 	sizeY := titleY + 50
 
 	colorX := (constants.ScreenWidth - font.MeasureString(fonts.RegularFont, colorText).Round()) / 2
-	colorY := sizeY + 50
+	colorY := sizeY + 40
+
+	wallsX := (constants.ScreenWidth - font.MeasureString(fonts.RegularFont, wallsText).Round()) / 2
+	wallsY := colorY + 40
+
+	levelX := (constants.ScreenWidth - font.MeasureString(fonts.RegularFont, levelText).Round()) / 2
+	levelY := wallsY + 40
+
+	powerUpsX := (constants.ScreenWidth - font.MeasureString(fonts.RegularFont, powerUpsText).Round()) / 2
+	powerUpsY := levelY + 40
+
+	playModeX := (constants.ScreenWidth - font.MeasureString(fonts.RegularFont, playModeText).Round()) / 2
+	playModeY := powerUpsY + 40
+
+	rivalsX := (constants.ScreenWidth - font.MeasureString(fonts.RegularFont, rivalsText).Round()) / 2
+	rivalsY := playModeY + 40
 
 	startX := (constants.ScreenWidth - font.MeasureString(fonts.RegularFont, startText).Round()) / 2
 	startY := constants.ScreenHeight - 50
 
+	campaignX := (constants.ScreenWidth - font.MeasureString(fonts.RegularFont, campaignText).Round()) / 2
+	campaignY := startY - 30
+
+	continueX := (constants.ScreenWidth - font.MeasureString(fonts.RegularFont, continueText).Round()) / 2
+	continueY := campaignY - 30
+
 	// Draw the text
 	text.Draw(screen, title, fonts.BigFont, titleX, titleY, color.White)
 	text.Draw(screen, sizeText, fonts.RegularFont, sizeX, sizeY, color.White)
 	text.Draw(screen, colorText, fonts.RegularFont, colorX, colorY, color.White)
+	text.Draw(screen, wallsText, fonts.RegularFont, wallsX, wallsY, color.White)
+	text.Draw(screen, levelText, fonts.RegularFont, levelX, levelY, color.White)
+	text.Draw(screen, powerUpsText, fonts.RegularFont, powerUpsX, powerUpsY, color.White)
+	text.Draw(screen, playModeText, fonts.RegularFont, playModeX, playModeY, color.White)
+	text.Draw(screen, rivalsText, fonts.RegularFont, rivalsX, rivalsY, color.White)
 	text.Draw(screen, startText, fonts.RegularFont, startX, startY, color.White)
+	text.Draw(screen, campaignText, fonts.RegularFont, campaignX, campaignY, color.White)
+	if g.canContinue {
+		text.Draw(screen, continueText, fonts.RegularFont, continueX, continueY, color.White)
+	}
 }
 
 func (g *Game) DrawGameOver(screen *ebiten.Image) {
@@ -153,9 +546,31 @@ func (g *Game) DrawGameOver(screen *ebiten.Image) {
 
 	// Set the positions for the text
 	gameOverText := "Game Over"
-	scoreText := "Score: " + strconv.Itoa(g.board.score)
-	pressSpaceText := "Press space to play again"
-	pressEscapeText := "Press escape to return to the title screen"
+	if g.board.state.Won() {
+		gameOverText = "Stage Clear"
+	}
+	if g.board.state.TimeUp() {
+		gameOverText = "Time's Up"
+	}
+	scoreText := "Score: " + strconv.Itoa(g.board.state.Score())
+	if versus(g.board.state) {
+		gameOverText = roundText(g.board.state)
+		scoreText = g.match.text()
+	}
+	seedText := "Seed: " + strconv.FormatUint(g.board.state.Seed(), 10)
+	confirm := keyText(ActionConfirm)
+	pressSpaceText := "Press " + confirm + " to play again"
+	if g.stage > 0 && g.board.state.Won() && g.stage < len(campaign) {
+		pressSpaceText = "Press " + confirm + " for the next stage"
+	}
+	if versus(g.board.state) {
+		pressSpaceText = "Press " + confirm + " for the next round"
+		if g.match.winner() >= 0 {
+			pressSpaceText = "Press " + confirm + " for a new match"
+		}
+	}
+	pressRText := "Press " + keyText(ActionReplay) + " to watch the replay"
+	pressEscapeText := "Press " + keyText(ActionBack) + " to return to the title screen"
 
 	gameOverX := (constants.ScreenWidth - font.MeasureString(fonts.BigFont, gameOverText).Round()) / 2
 	gameOverY := (constants.ScreenHeight / 2) - 150
@@ -163,15 +578,23 @@ func (g *Game) DrawGameOver(screen *ebiten.Image) {
 	scoreX := (constants.ScreenWidth - font.MeasureString(fonts.RegularFont, scoreText).Round()) / 2
 	scoreY := gameOverY + 50
 
+	seedX := (constants.ScreenWidth - font.MeasureString(fonts.RegularFont, seedText).Round()) / 2
+	seedY := scoreY + 30
+
 	pressStartX := (constants.ScreenWidth - font.MeasureString(fonts.RegularFont, pressSpaceText).Round()) / 2
 	pressStartY := constants.ScreenHeight - 50
 
+	pressRX := (constants.ScreenWidth - font.MeasureString(fonts.RegularFont, pressRText).Round()) / 2
+	pressRY := pressStartY - 30
+
 	pressEscapeX := (constants.ScreenWidth - font.MeasureString(fonts.RegularFont, pressEscapeText).Round()) / 2
 	pressEscapeY := pressStartY + 30
 
 	// Draw the text
 	text.Draw(screen, gameOverText, fonts.BigFont, gameOverX, gameOverY, color.White)
 	text.Draw(screen, scoreText, fonts.RegularFont, scoreX, scoreY, color.White)
+	text.Draw(screen, seedText, fonts.RegularFont, seedX, seedY, color.White)
 	text.Draw(screen, pressSpaceText, fonts.RegularFont, pressStartX, pressStartY, color.White)
+	text.Draw(screen, pressRText, fonts.RegularFont, pressRX, pressRY, color.White)
 	text.Draw(screen, pressEscapeText, fonts.RegularFont, pressEscapeX, pressEscapeY, color.White)
 }
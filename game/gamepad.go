@@ -0,0 +1,263 @@
+package game
+
+import (
+	"fmt"
+	"image/color"
+	"math"
+	"slices"
+
+	"github.com/adan-ea/GoSnakeGo/constants"
+	"github.com/adan-ea/GoSnakeGo/resources/fonts"
+	"github.com/adan-ea/GoSnakeGo/sim"
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+	"github.com/hajimehoshi/ebiten/v2/text"
+	"golang.org/x/image/font"
+)
+
+const (
+	// stickDeadzone is how far the left stick must be pushed to point to a
+	// direction, from 0 to 1
+	stickDeadzone = 0.5
+	// noticeTicks is how long a gamepad being plugged or unplugged is shown
+	noticeTicks = 2 * sim.TicksPerSecond
+)
+
+// padButtons are the buttons of the standard layout doing the actions other
+// than the directions. Like keys, a button does one action on each screen:
+// Back goes back, except on the title screen which opens the settings with it.
+var padButtons = map[Action][]ebiten.StandardGamepadButton{
+	ActionConfirm:  {ebiten.StandardGamepadButtonRightBottom},
+	ActionContinue: {ebiten.StandardGamepadButtonRightTop},
+	ActionBack:     {ebiten.StandardGamepadButtonRightRight, ebiten.StandardGamepadButtonCenterLeft},
+	ActionPause:    {ebiten.StandardGamepadButtonCenterRight},
+	ActionCampaign: {ebiten.StandardGamepadButtonFrontTopRight},
+	ActionWatch:    {ebiten.StandardGamepadButtonFrontTopLeft},
+	ActionSettings: {ebiten.StandardGamepadButtonCenterLeft},
+	ActionReplay:   {ebiten.StandardGamepadButtonRightLeft},
+	ActionStep:     {ebiten.StandardGamepadButtonRightLeft},
+	ActionStrategy: {ebiten.StandardGamepadButtonRightLeft},
+}
+
+// padDirections are the buttons of the D-pad
+var padDirections = map[sim.Direction]ebiten.StandardGamepadButton{
+	sim.Up:    ebiten.StandardGamepadButtonLeftTop,
+	sim.Left:  ebiten.StandardGamepadButtonLeftLeft,
+	sim.Right: ebiten.StandardGamepadButtonLeftRight,
+	sim.Down:  ebiten.StandardGamepadButtonLeftBottom,
+}
+
+// direction returns the player and the direction of a direction action
+func (a Action) direction() (int, sim.Direction, bool) {
+	switch a {
+	case ActionUp:
+		return 1, sim.Up, true
+	case ActionDown:
+		return 1, sim.Down, true
+	case ActionLeft:
+		return 1, sim.Left, true
+	case ActionRight:
+		return 1, sim.Right, true
+	case ActionUp2:
+		return 2, sim.Up, true
+	case ActionDown2:
+		return 2, sim.Down, true
+	case ActionLeft2:
+		return 2, sim.Left, true
+	case ActionRight2:
+		return 2, sim.Right, true
+	}
+	return 0, 0, false
+}
+
+// gamepad is a connected gamepad with the standard layout
+type gamepad struct {
+	id     ebiten.GamepadID
+	player int // 1 or 2, the player whose snake it drives in versus
+	// direction the left stick points to, and whether it started pointing
+	// there during this update
+	stick   sim.Direction
+	held    bool
+	flicked bool
+}
+
+// updateStick reads the left stick, it points to the direction of its
+// largest axis once out of the deadzone
+func (pad *gamepad) updateStick() {
+	x := ebiten.StandardGamepadAxisValue(pad.id, ebiten.StandardGamepadAxisLeftStickHorizontal)
+	y := ebiten.StandardGamepadAxisValue(pad.id, ebiten.StandardGamepadAxisLeftStickVertical)
+
+	held := math.Hypot(x, y) >= stickDeadzone
+	var dir sim.Direction
+	switch {
+	case math.Abs(x) > math.Abs(y) && x < 0:
+		dir = sim.Left
+	case math.Abs(x) > math.Abs(y):
+		dir = sim.Right
+	case y < 0:
+		dir = sim.Up
+	default:
+		dir = sim.Down
+	}
+
+	pad.flicked = held && (!pad.held || dir != pad.stick)
+	pad.stick, pad.held = dir, held
+}
+
+// dirPressed reports whether the direction was pressed on the D-pad or the
+// stick during this update
+func (pad *gamepad) dirPressed(dir sim.Direction) bool {
+	return (pad.flicked && pad.stick == dir) ||
+		inpututil.IsStandardGamepadButtonJustPressed(pad.id, padDirections[dir])
+}
+
+// gamepads are the connected gamepads in the order they were plugged
+type gamepads struct {
+	pads   []*gamepad
+	notice string // last gamepad plugged or unplugged, shown for a moment
+	ticks  int    // ticks left showing the notice
+}
+
+// pads are updated at the start of every update, before reading the actions
+var pads gamepads
+
+// update follows the gamepads being plugged and unplugged and reads their sticks
+func (p *gamepads) update() {
+	p.pads = slices.DeleteFunc(p.pads, func(pad *gamepad) bool {
+		if !inpututil.IsGamepadJustDisconnected(pad.id) {
+			return false
+		}
+		p.notify(fmt.Sprintf("Gamepad of player %d unplugged", pad.player))
+		return true
+	})
+
+	for _, id := range inpututil.AppendJustConnectedGamepadIDs(nil) {
+		if !ebiten.IsStandardGamepadLayoutAvailable(id) {
+			p.notify(ebiten.GamepadName(id) + " is not supported")
+			continue
+		}
+		pad := &gamepad{id: id, player: p.freePlayer()}
+		p.pads = append(p.pads, pad)
+		p.notify(fmt.Sprintf("%s plugged for player %d", ebiten.GamepadName(id), pad.player))
+	}
+
+	for _, pad := range p.pads {
+		pad.updateStick()
+	}
+	if p.ticks > 0 {
+		p.ticks--
+	}
+}
+
+// freePlayer returns the player with the fewest gamepads, player 1 first
+func (p *gamepads) freePlayer() int {
+	count := [3]int{}
+	for _, pad := range p.pads {
+		count[pad.player]++
+	}
+	if count[2] < count[1] {
+		return 2
+	}
+	return 1
+}
+
+func (p *gamepads) notify(notice string) {
+	p.notice = notice
+	p.ticks = noticeTicks
+}
+
+// pressed reports whether a gamepad did the action during this update, the
+// directions of a player only count on the gamepads given to that player
+func (p *gamepads) pressed(a Action) bool {
+	if player, dir, ok := a.direction(); ok {
+		for _, pad := range p.pads {
+			if pad.player == player && pad.dirPressed(dir) {
+				return true
+			}
+		}
+		return false
+	}
+
+	for _, pad := range p.pads {
+		for _, button := range padButtons[a] {
+			if inpututil.IsStandardGamepadButtonJustPressed(pad.id, button) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// drawNotice shows the last gamepad plugged or unplugged over any screen
+func (p *gamepads) drawNotice(screen *ebiten.Image) {
+	if p.ticks == 0 {
+		return
+	}
+	x := (constants.ScreenWidth - font.MeasureString(fonts.RegularFont, p.notice).Round()) / 2
+	text.Draw(screen, p.notice, fonts.RegularFont, x, 30, constants.LightBlue)
+}
+
+// openGamepads shows the gamepads, leaving them goes back to the settings
+func (g *Game) openGamepads() {
+	g.gamepadsMenu = newMenu(gamepadsItems()...)
+	g.mode = ModeGamepads
+}
+
+// updateGamepads gives the selected gamepad to the other player with the
+// directions or Confirm
+func (g *Game) updateGamepads() {
+	m := g.gamepadsMenu
+	if Pressed(ActionBack) {
+		g.mode = ModeSettings
+		return
+	}
+
+	// the list follows the gamepads being plugged and unplugged
+	m.items = gamepadsItems()
+	m.selected = min(m.selected, len(m.items)-1)
+
+	swap := false
+	if dir, ok := Dir(); ok && (dir == sim.Left || dir == sim.Right) {
+		swap = true
+	}
+	choice, ok := m.Update()
+	if ok && choice == len(pads.pads) {
+		g.mode = ModeSettings
+		return
+	}
+	if (swap || ok) && m.selected < len(pads.pads) {
+		pad := pads.pads[m.selected]
+		pad.player = 3 - pad.player
+	}
+	m.items = gamepadsItems()
+}
+
+// gamepadsItems returns an item with the player of each gamepad
+func gamepadsItems() []string {
+	items := make([]string, 0, len(pads.pads)+1)
+	for _, pad := range pads.pads {
+		items = append(items, fmt.Sprintf("%s: Player %d", ebiten.GamepadName(pad.id), pad.player))
+	}
+	return append(items, "Back")
+}
+
+func (g *Game) drawGamepads(screen *ebiten.Image) {
+	if g.settingsFrom == ModePause {
+		g.board.Draw(screen)
+		drawOverlay(screen)
+	}
+
+	title := "Gamepads"
+	titleX := (constants.ScreenWidth - font.MeasureString(fonts.BigFont, title).Round()) / 2
+	titleY := (constants.ScreenHeight / 2) - 150
+	text.Draw(screen, title, fonts.BigFont, titleX, titleY, color.White)
+
+	g.gamepadsMenu.Draw(screen, titleY+80)
+
+	hint := "Left/Right to give a gamepad to the other player"
+	if len(pads.pads) == 0 {
+		hint = "Plug a gamepad with the standard layout"
+	}
+	hintX := (constants.ScreenWidth - font.MeasureString(fonts.RegularFont, hint).Round()) / 2
+	text.Draw(screen, hint, fonts.RegularFont, hintX, constants.ScreenHeight-50, color.White)
+}
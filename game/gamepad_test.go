@@ -0,0 +1,21 @@
+package game
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestPadButtonsDoNotConflict(t *testing.T) {
+	for a, buttons := range padButtons {
+		for other, otherButtons := range padButtons {
+			if other == a || !a.sharesScreen(other) {
+				continue
+			}
+			for _, button := range buttons {
+				if slices.Contains(otherButtons, button) {
+					t.Errorf("%v and %v share button %d", a, other, button)
+				}
+			}
+		}
+	}
+}
@@ -1,6 +1,10 @@
 package game
 
-import "github.com/hajimehoshi/ebiten/v2"
+import (
+	"github.com/adan-ea/GoSnakeGo/sim"
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+)
 
 type Input struct{}
 
@@ -8,35 +12,80 @@ func newInput() *Input {
 	return &Input{}
 }
 
-func Dir() (Direction, bool) {
-	if ebiten.IsKeyPressed(ebiten.KeyArrowUp) || ebiten.IsKeyPressed(ebiten.KeyW) {
-		return Up, true
-	}
-	if ebiten.IsKeyPressed(ebiten.KeyArrowLeft) || ebiten.IsKeyPressed(ebiten.KeyA) {
-		return Left, true
-	}
-	if ebiten.IsKeyPressed(ebiten.KeyArrowRight) || ebiten.IsKeyPressed(ebiten.KeyD) {
-		return Right, true
+// bindings are the keys of the actions and keyLayout how they are named, set
+// from the settings when the game starts
+var (
+	bindings  = defaultBindings()
+	keyLayout Layout
+)
+
+// Pressed reports whether a key or a gamepad button of the action was
+// pressed during this update
+func Pressed(a Action) bool {
+	for _, key := range bindings.keys(a) {
+		if inpututil.IsKeyJustPressed(key) {
+			return true
+		}
 	}
-	if ebiten.IsKeyPressed(ebiten.KeyArrowDown) || ebiten.IsKeyPressed(ebiten.KeyS) {
-		return Down, true
+	return pads.pressed(a)
+}
+
+// keyText returns the name of the first key of the action, shown in the
+// hints of the screens
+func keyText(a Action) string {
+	keys := bindings.keys(a)
+	if len(keys) == 0 {
+		return "-"
 	}
+	return keyLayout.keyName(keys[0])
+}
 
-	return 0, false
+// Dir returns the direction pressed by either player during this update
+func Dir() (sim.Direction, bool) {
+	if dir, ok := DirPlayer2(); ok {
+		return dir, true
+	}
+	return DirPlayer1()
 }
 
-func KeyS() bool {
-	return ebiten.IsKeyPressed(ebiten.KeyS)
+// DirPlayer1 returns the direction pressed with player 1's keys, WASD by
+// default, or gamepads during this update
+func DirPlayer1() (sim.Direction, bool) {
+	return dirFromActions(ActionUp, ActionLeft, ActionRight, ActionDown)
 }
 
-func KeyC() bool {
-	return ebiten.IsKeyPressed(ebiten.KeyC)
+// DirPlayer2 returns the direction pressed with player 2's keys, the arrows
+// by default, or gamepads during this update
+func DirPlayer2() (sim.Direction, bool) {
+	return dirFromActions(ActionUp2, ActionLeft2, ActionRight2, ActionDown2)
 }
 
-func Space() bool {
-	return ebiten.IsKeyPressed(ebiten.KeySpace)
+func dirFromActions(up, left, right, down Action) (sim.Direction, bool) {
+	if Pressed(up) {
+		return sim.Up, true
+	}
+	if Pressed(left) {
+		return sim.Left, true
+	}
+	if Pressed(right) {
+		return sim.Right, true
+	}
+	if Pressed(down) {
+		return sim.Down, true
+	}
+
+	return 0, false
 }
 
-func Escape() bool {
-	return ebiten.IsKeyPressed(ebiten.KeyEscape)
+// AnyKey reports whether a key or a gamepad button was pressed during this update
+func AnyKey() bool {
+	if len(inpututil.AppendJustPressedKeys(nil)) > 0 {
+		return true
+	}
+	for _, id := range ebiten.AppendGamepadIDs(nil) {
+		if len(inpututil.AppendJustPressedStandardGamepadButtons(id, nil)) > 0 {
+			return true
+		}
+	}
+	return false
 }
@@ -0,0 +1,61 @@
+package game
+
+import (
+	"image/color"
+
+	"github.com/adan-ea/GoSnakeGo/constants"
+	"github.com/adan-ea/GoSnakeGo/resources/fonts"
+	"github.com/adan-ea/GoSnakeGo/sim"
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/text"
+	"golang.org/x/image/font"
+)
+
+// menu is a vertical list of items navigated with the direction keys
+type menu struct {
+	items    []string
+	selected int
+	rows     int // items shown at once around the selected one, all of them when zero
+}
+
+func newMenu(items ...string) *menu {
+	return &menu{items: items}
+}
+
+// Update moves the selection and returns the index of the item chosen with
+// the keys of Confirm or Continue, if any
+func (m *menu) Update() (int, bool) {
+	if dir, ok := Dir(); ok {
+		switch dir {
+		case sim.Up:
+			m.selected = (m.selected + len(m.items) - 1) % len(m.items)
+		case sim.Down:
+			m.selected = (m.selected + 1) % len(m.items)
+		}
+	}
+
+	if Pressed(ActionContinue) || Pressed(ActionConfirm) {
+		return m.selected, true
+	}
+	return 0, false
+}
+
+// Draw renders the items centered horizontally starting at the given height
+func (m *menu) Draw(screen *ebiten.Image, y int) {
+	first, last := 0, len(m.items)
+	if m.rows > 0 && len(m.items) > m.rows {
+		first = min(max(m.selected-m.rows/2, 0), len(m.items)-m.rows)
+		last = first + m.rows
+	}
+
+	for i, item := range m.items[first:last] {
+		clr := color.Color(color.White)
+		if first+i == m.selected {
+			item = "> " + item + " <"
+			clr = constants.LightBlue
+		}
+
+		x := (constants.ScreenWidth - font.MeasureString(fonts.RegularFont, item).Round()) / 2
+		text.Draw(screen, item, fonts.RegularFont, x, y+i*40, clr)
+	}
+}
@@ -0,0 +1,174 @@
+package game
+
+import (
+	"fmt"
+	"image/color"
+
+	"github.com/adan-ea/GoSnakeGo/constants"
+	"github.com/adan-ea/GoSnakeGo/resources/audio"
+	"github.com/adan-ea/GoSnakeGo/resources/fonts"
+	"github.com/adan-ea/GoSnakeGo/sim"
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/text"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+	"golang.org/x/image/font"
+)
+
+// Items of the pause menu
+const (
+	pauseResume = iota
+	pauseRestart
+	pauseSettings
+	pauseQuit
+)
+
+// Items of the settings menu
+const (
+	settingsMusic = iota
+	settingsSounds
+	settingsFullscreen
+	settingsControls
+	settingsGamepads
+	settingsBack
+)
+
+// Step used when changing a volume in the settings
+const volumeStep = 0.1
+
+var overlayColor = color.RGBA{A: 160}
+
+// pause freezes the board and the music
+func (g *Game) pause() {
+	audio.ThemePlayer.Pause()
+	g.pauseMenu = newMenu("Resume", "Restart", "Settings", "Quit to title")
+	g.mode = ModePause
+}
+
+// resume continues the board where it was frozen. The board only moves on
+// ticks, so the time until the next move is the same as when it was paused.
+func (g *Game) resume() {
+	audio.ThemePlayer.Play()
+	g.mode = ModeGame
+}
+
+func (g *Game) updatePause() {
+	if Pressed(ActionBack) || Pressed(ActionPause) {
+		g.resume()
+		return
+	}
+
+	choice, ok := g.pauseMenu.Update()
+	if !ok {
+		return
+	}
+
+	switch choice {
+	case pauseResume:
+		g.resume()
+	case pauseRestart:
+		audio.ThemePlayer.Rewind()
+		g.board = g.newBoard()
+		g.resume()
+	case pauseSettings:
+		g.openSettings(ModePause)
+	case pauseQuit:
+		// keep the run so it can be continued from the title screen
+		g.autosave()
+		g.mode = ModeTitle
+	}
+}
+
+func (g *Game) drawPause(screen *ebiten.Image) {
+	g.board.Draw(screen)
+	drawOverlay(screen)
+
+	title := "Paused"
+	titleX := (constants.ScreenWidth - font.MeasureString(fonts.BigFont, title).Round()) / 2
+	titleY := (constants.ScreenHeight / 2) - 150
+	text.Draw(screen, title, fonts.BigFont, titleX, titleY, color.White)
+
+	g.pauseMenu.Draw(screen, titleY+80)
+}
+
+// drawOverlay darkens everything drawn so far
+func drawOverlay(screen *ebiten.Image) {
+	vector.DrawFilledRect(screen, 0, 0, constants.ScreenWidth, constants.ScreenHeight, overlayColor, false)
+}
+
+// openSettings shows the settings and goes back to the given mode when leaving them
+func (g *Game) openSettings(from Mode) {
+	g.settingsMenu = newMenu(settingsItems()...)
+	g.settingsFrom = from
+	g.mode = ModeSettings
+}
+
+func (g *Game) updateSettings() {
+	if Pressed(ActionBack) {
+		g.mode = g.settingsFrom
+		return
+	}
+
+	if dir, ok := Dir(); ok && (dir == sim.Left || dir == sim.Right) {
+		delta := volumeStep
+		if dir == sim.Left {
+			delta = -volumeStep
+		}
+
+		switch g.settingsMenu.selected {
+		case settingsMusic:
+			audio.MusicVolume = clampVolume(audio.MusicVolume + delta)
+			audio.ThemePlayer.SetVolume(audio.MusicVolume)
+		case settingsSounds:
+			audio.SoundVolume = clampVolume(audio.SoundVolume + delta)
+		case settingsFullscreen:
+			ebiten.SetFullscreen(!ebiten.IsFullscreen())
+		}
+	}
+
+	if choice, ok := g.settingsMenu.Update(); ok {
+		switch choice {
+		case settingsFullscreen:
+			ebiten.SetFullscreen(!ebiten.IsFullscreen())
+		case settingsControls:
+			g.openControls()
+		case settingsGamepads:
+			g.openGamepads()
+		case settingsBack:
+			g.mode = g.settingsFrom
+		}
+	}
+	g.settingsMenu.items = settingsItems()
+}
+
+func settingsItems() []string {
+	return []string{
+		fmt.Sprintf("Music: %d%%", volumePercent(audio.MusicVolume)),
+		fmt.Sprintf("Sounds: %d%%", volumePercent(audio.SoundVolume)),
+		"Fullscreen: " + getOnOffText(ebiten.IsFullscreen()),
+		"Controls",
+		"Gamepads",
+		"Back",
+	}
+}
+
+func (g *Game) drawSettings(screen *ebiten.Image) {
+	if g.settingsFrom == ModePause {
+		g.board.Draw(screen)
+		drawOverlay(screen)
+	}
+
+	title := "Settings"
+	titleX := (constants.ScreenWidth - font.MeasureString(fonts.BigFont, title).Round()) / 2
+	titleY := (constants.ScreenHeight / 2) - 150
+	text.Draw(screen, title, fonts.BigFont, titleX, titleY, color.White)
+
+	g.settingsMenu.Draw(screen, titleY+80)
+}
+
+func clampVolume(v float64) float64 {
+	return min(max(v, 0), 1)
+}
+
+func volumePercent(v float64) int {
+	return int(v*100 + 0.5)
+}
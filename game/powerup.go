@@ -0,0 +1,42 @@
+package game
+
+import (
+	"github.com/adan-ea/GoSnakeGo/constants"
+	"github.com/adan-ea/GoSnakeGo/resources/images"
+	"github.com/adan-ea/GoSnakeGo/sim"
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// Horizontal space taken by each effect timer in the HUD
+const effectSpacing = 90
+
+// drawPowerUp renders the power-up on the screen, it blinks before it disappears
+func drawPowerUp(screen *ebiten.Image, p *sim.PowerUp, ticks int, offsetX, offsetY int) {
+	if left := p.Expires() - ticks; left < blinkTicks && left/6%2 == 0 {
+		return
+	}
+
+	pos := p.Pos()
+	sx := float64(offsetX + pos.X*constants.TileSize)
+	sy := float64(offsetY + pos.Y*constants.TileSize)
+
+	op := &ebiten.DrawImageOptions{}
+	op.GeoM.Translate(sx, sy)
+	screen.DrawImage(images.PowerUpSprite[int(p.Kind())], op)
+}
+
+// drawEffects renders the seconds left of each effect active on the snake
+// next to its score, moving by step after each one
+func (b *Board) drawEffects(screen *ebiten.Image, snake *sim.Snake, x, y, step int) {
+	for kind := sim.PowerKind(0); kind < sim.NbPowerKinds; kind++ {
+		left := b.state.Effect(snake, kind)
+		if left == 0 {
+			continue
+		}
+
+		// round up so the timer shows 1 during the last second
+		seconds := (left + sim.TicksPerSecond - 1) / sim.TicksPerSecond
+		b.DrawScoreWithSprite(screen, images.PowerUpSprite[int(kind)], seconds, x, y)
+		x += step
+	}
+}
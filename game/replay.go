@@ -0,0 +1,110 @@
+package game
+
+import (
+	"fmt"
+	"image/color"
+	"log"
+	"path/filepath"
+	"time"
+
+	"github.com/adan-ea/GoSnakeGo/constants"
+	"github.com/adan-ea/GoSnakeGo/replay"
+	"github.com/adan-ea/GoSnakeGo/resources/fonts"
+	"github.com/adan-ea/GoSnakeGo/scoreboard"
+	"github.com/adan-ea/GoSnakeGo/sim"
+	"github.com/adan-ea/GoSnakeGo/storage"
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/text"
+)
+
+// Playback speeds available in the replay viewer
+var replaySpeeds = []float64{0.25, 0.5, 1, 2, 4, 8}
+
+const (
+	normalReplaySpeed = 2                      // index of 1x in replaySpeeds
+	replaySeekStep    = 5 * sim.TicksPerSecond // ticks skipped when seeking
+)
+
+// replayViewer plays a replay back with pause, step, seek and speed controls
+type replayViewer struct {
+	player   *replay.Player
+	board    *Board
+	paused   bool
+	speed    int
+	progress float64 // ticks owed to the player at the current speed
+}
+
+func newReplayViewer(r *replay.Replay) *replayViewer {
+	player := replay.NewPlayer(r)
+
+	return &replayViewer{
+		player: player,
+		board: &Board{
+			state:     player.Board(),
+			sprite:    newSnakeSprite(),
+			highScore: scoreboard.Best(scoreboard.Table(player.Board())),
+		},
+		speed: normalReplaySpeed,
+	}
+}
+
+func (v *replayViewer) Update() {
+	if Pressed(ActionConfirm) {
+		v.paused = !v.paused
+	}
+	if dir, ok := Dir(); ok {
+		switch {
+		case dir == sim.Up && v.speed < len(replaySpeeds)-1:
+			v.speed++
+		case dir == sim.Down && v.speed > 0:
+			v.speed--
+		case dir == sim.Right:
+			v.player.Seek(v.player.Board().Ticks() + replaySeekStep)
+		case dir == sim.Left:
+			v.player.Seek(max(v.player.Board().Ticks()-replaySeekStep, 0))
+		}
+	}
+
+	if v.paused {
+		if Pressed(ActionStep) {
+			v.player.Step()
+		}
+	} else {
+		v.progress += replaySpeeds[v.speed]
+		for v.progress >= 1 {
+			v.progress--
+			v.player.Step()
+		}
+	}
+
+	// seeking backwards restarts the replay on a new board
+	v.board.state = v.player.Board()
+}
+
+func (v *replayViewer) Draw(screen *ebiten.Image) {
+	v.board.Draw(screen)
+
+	ticks := v.player.Board().Ticks()
+	length := v.player.Replay().Length
+	status := fmt.Sprintf("%gx  %.1fs / %.1fs", replaySpeeds[v.speed],
+		float64(ticks)/sim.TicksPerSecond, float64(length)/sim.TicksPerSecond)
+	if v.paused {
+		status += "  Paused"
+	}
+
+	text.Draw(screen, status, fonts.RegularFont, 10, constants.ScreenHeight-10, color.White)
+}
+
+// saveReplay writes the replay of a finished game to the replays directory
+func saveReplay(r *replay.Replay) {
+	dir, err := storage.Dir("replays")
+	if err != nil {
+		log.Println(err)
+		return
+	}
+
+	name := time.Now().Format("2006-01-02_15-04-05") + replay.Extension
+	if err := r.Save(filepath.Join(dir, name)); err != nil {
+		log.Println(err)
+	}
+}
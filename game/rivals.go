@@ -0,0 +1,41 @@
+package game
+
+import (
+	"fmt"
+
+	"github.com/adan-ea/GoSnakeGo/bot"
+	"github.com/adan-ea/GoSnakeGo/sim"
+)
+
+// Most rival snakes that can share the board with the players
+const maxRivals = 2
+
+// rivalSnakes returns n rival snakes coming after the first snakes of the
+// board, each in the color after the previous snake's
+func rivalSnakes(color sim.Color, first, n int) []sim.SnakeConfig {
+	rivals := make([]sim.SnakeConfig, n)
+	for i := range rivals {
+		rivals[i] = sim.SnakeConfig{Color: color, Bot: true}
+		if color != sim.RandomColor {
+			rivals[i].Color = (color + sim.Color(first+i)) % (sim.NbColors - 1)
+		}
+	}
+	return rivals
+}
+
+func getRivalsText(rivals int, difficulty bot.Difficulty) string {
+	if rivals == 0 {
+		return "None"
+	}
+	return fmt.Sprintf("%d %s", rivals, getDifficultyText(difficulty))
+}
+
+func getDifficultyText(difficulty bot.Difficulty) string {
+	switch difficulty {
+	case bot.Cautious:
+		return "Cautious"
+	case bot.Pathfinding:
+		return "Pathfinding"
+	}
+	return "Greedy"
+}
@@ -0,0 +1,130 @@
+package game
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+
+	"github.com/adan-ea/GoSnakeGo/bot"
+	"github.com/adan-ea/GoSnakeGo/replay"
+	"github.com/adan-ea/GoSnakeGo/scoreboard"
+	"github.com/adan-ea/GoSnakeGo/sim"
+	"github.com/adan-ea/GoSnakeGo/storage"
+)
+
+const (
+	saveFileName = "save.json"
+	saveVersion  = 3
+)
+
+// saveFile is the content of the file an in-progress game is saved to
+type saveFile struct {
+	Version int            `json:"version"`
+	Board   sim.State      `json:"board"`
+	Replay  *replay.Replay `json:"replay"`
+	Stage   int            `json:"stage,omitempty"` // campaign stage plus one, 0 outside of the campaign
+	Match   match          `json:"match"`           // rounds won so far in versus
+	// how the rival snakes play
+	Difficulty bot.Difficulty `json:"difficulty"`
+}
+
+func savePath() (string, error) {
+	dir, err := storage.Dir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, saveFileName), nil
+}
+
+// saveGame writes the board to the save file so it can be resumed later
+func saveGame(b *Board, stage int, m match) error {
+	state, err := b.state.Snapshot()
+	if err != nil {
+		return err
+	}
+
+	data, err := json.Marshal(saveFile{
+		Version:    saveVersion,
+		Board:      state,
+		Replay:     b.replay,
+		Stage:      stage,
+		Match:      m,
+		Difficulty: b.difficulty,
+	})
+	if err != nil {
+		return err
+	}
+
+	path, err := savePath()
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0644)
+}
+
+// loadGame restores the board, its campaign stage and its versus match from the save file
+func loadGame() (*Board, int, match, error) {
+	path, err := savePath()
+	if err != nil {
+		return nil, 0, match{}, err
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, 0, match{}, err
+	}
+
+	var save saveFile
+	if err := json.Unmarshal(data, &save); err != nil {
+		return nil, 0, match{}, err
+	}
+	if save.Version != saveVersion {
+		return nil, 0, match{}, fmt.Errorf("save file version %d is not supported, expected version %d", save.Version, saveVersion)
+	}
+	if save.Replay == nil {
+		return nil, 0, match{}, errors.New("save file has no replay")
+	}
+	if save.Stage < 0 || save.Stage > len(campaign) {
+		return nil, 0, match{}, fmt.Errorf("save file has unknown campaign stage %d", save.Stage)
+	}
+
+	state, err := sim.RestoreBoard(save.Board)
+	if err != nil {
+		return nil, 0, match{}, err
+	}
+
+	board := &Board{
+		state:     state,
+		sprite:    newSnakeSprite(),
+		highScore: scoreboard.Best(scoreboard.Table(state)),
+		table:     scoreboard.Table(state),
+		replay:    save.Replay,
+	}
+	board.setControllers(save.Difficulty)
+
+	return board, save.Stage, save.Match, nil
+}
+
+// hasSavedGame reports whether there is a game to continue
+func hasSavedGame() bool {
+	path, err := savePath()
+	if err != nil {
+		return false
+	}
+	_, err = os.Stat(path)
+	return err == nil
+}
+
+// deleteSavedGame removes the save file once its game is over
+func deleteSavedGame() {
+	path, err := savePath()
+	if err != nil {
+		return
+	}
+	if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		log.Println(err)
+	}
+}
@@ -0,0 +1,212 @@
+package game
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"os"
+	"path/filepath"
+
+	"github.com/adan-ea/GoSnakeGo/constants"
+	"github.com/adan-ea/GoSnakeGo/sim"
+	"github.com/adan-ea/GoSnakeGo/storage"
+)
+
+const (
+	settingsFileName = "settings.json"
+	// settingsVersion is the version of the settings file, raised when the
+	// meaning of a field changes
+	settingsVersion = 1
+)
+
+// choices are the settings the player changes while playing, compared every
+// update to know when to save them
+type choices struct {
+	Size         sim.Size  `json:"size"`
+	Rows         int       `json:"rows"`
+	Cols         int       `json:"cols"`
+	Color        sim.Color `json:"color"`
+	Mode         PlayMode  `json:"mode"`
+	MusicVolume  float64   `json:"musicVolume"`
+	SoundVolume  float64   `json:"soundVolume"`
+	WindowWidth  int       `json:"windowWidth"`
+	WindowHeight int       `json:"windowHeight"`
+	Fullscreen   bool      `json:"fullscreen"`
+}
+
+// Settings are the options the game remembers between launches, kept in the
+// settings file of the user config directory
+type Settings struct {
+	Version int `json:"version"`
+	choices
+	Keys   Bindings `json:"keys"`
+	Layout Layout   `json:"layout"` // how the keys are named
+
+	// fields written by a newer version of the game, kept as they were so
+	// going back to an older version does not lose them
+	unknown map[string]json.RawMessage
+}
+
+// DefaultSettings returns the settings of a first launch
+func DefaultSettings() *Settings {
+	return &Settings{
+		Version: settingsVersion,
+		choices: choices{
+			Size:         sim.Small,
+			Rows:         defaultRows,
+			Cols:         defaultCols,
+			Color:        sim.Blue,
+			Mode:         PlayClassic,
+			MusicVolume:  1,
+			SoundVolume:  1,
+			WindowWidth:  constants.ScreenWidth,
+			WindowHeight: constants.ScreenHeight,
+		},
+		Keys: defaultBindings(),
+	}
+}
+
+func settingsPath() (string, error) {
+	dir, err := storage.Dir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, settingsFileName), nil
+}
+
+// LoadSettings reads the settings file, falling back to the defaults for
+// the settings it is missing or gets wrong
+func LoadSettings() *Settings {
+	s := DefaultSettings()
+
+	path, err := settingsPath()
+	if err != nil {
+		log.Println(err)
+		return s
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if !errors.Is(err, os.ErrNotExist) {
+			log.Println(err)
+		}
+		return s
+	}
+
+	if err := json.Unmarshal(data, s); err != nil {
+		log.Println(err)
+	}
+	s.keepUnknown(data)
+	s.sanitize()
+	return s
+}
+
+// keepUnknown remembers the fields of the file this version does not know
+func (s *Settings) keepUnknown(data []byte) {
+	var fields map[string]json.RawMessage
+	if json.Unmarshal(data, &fields) != nil {
+		return
+	}
+
+	known, err := s.fields()
+	if err != nil {
+		return
+	}
+	for name, value := range fields {
+		if _, ok := known[name]; !ok {
+			if s.unknown == nil {
+				s.unknown = map[string]json.RawMessage{}
+			}
+			s.unknown[name] = value
+		}
+	}
+}
+
+// fields returns the settings as JSON fields by name
+func (s *Settings) fields() (map[string]json.RawMessage, error) {
+	data, err := json.Marshal(s)
+	if err != nil {
+		return nil, err
+	}
+	var fields map[string]json.RawMessage
+	err = json.Unmarshal(data, &fields)
+	return fields, err
+}
+
+// sanitize replaces the settings out of range with their default
+func (s *Settings) sanitize() {
+	def := DefaultSettings()
+
+	// a newer version keeps its number so it knows its fields are untouched
+	s.Version = max(s.Version, settingsVersion)
+	if s.Size < 0 || s.Size >= sim.NbSize {
+		s.Size = def.Size
+	}
+	if s.Rows <= 0 || s.Cols <= 0 {
+		s.Rows, s.Cols = def.Rows, def.Cols
+	}
+	s.Rows, s.Cols = sim.ClampSide(s.Rows), sim.ClampSide(s.Cols)
+	if s.Color < 0 || s.Color >= sim.NbColors {
+		s.Color = def.Color
+	}
+	if s.Mode < 0 || s.Mode >= NbPlayModes {
+		s.Mode = def.Mode
+	}
+	s.MusicVolume = clampVolume(s.MusicVolume)
+	s.SoundVolume = clampVolume(s.SoundVolume)
+	if s.WindowWidth <= 0 || s.WindowHeight <= 0 {
+		s.WindowWidth, s.WindowHeight = def.WindowWidth, def.WindowHeight
+	}
+
+	if s.Layout < 0 || s.Layout >= NbLayouts {
+		s.Layout = def.Layout
+	}
+	if s.Keys == nil {
+		s.Keys = Bindings{}
+	}
+	for name, keys := range def.Keys {
+		if len(s.Keys[name]) == 0 {
+			s.Keys[name] = keys
+		}
+	}
+}
+
+// Options returns the options of a game launched with the settings
+func (s *Settings) Options() Options {
+	return Options{
+		Size:       s.Size,
+		Rows:       s.Rows,
+		Cols:       s.Cols,
+		Color:      s.Color,
+		Mode:       s.Mode,
+		Fullscreen: s.Fullscreen,
+		Settings:   s,
+	}
+}
+
+// save writes the settings file along with the fields it did not know
+func (s *Settings) save() {
+	fields, err := s.fields()
+	if err != nil {
+		log.Println(err)
+		return
+	}
+	for name, value := range s.unknown {
+		fields[name] = value
+	}
+
+	data, err := json.MarshalIndent(fields, "", "  ")
+	if err != nil {
+		log.Println(err)
+		return
+	}
+
+	path, err := settingsPath()
+	if err != nil {
+		log.Println(err)
+		return
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		log.Println(err)
+	}
+}
@@ -0,0 +1,97 @@
+package game
+
+import (
+	"encoding/json"
+	"os"
+	"testing"
+
+	"github.com/adan-ea/GoSnakeGo/sim"
+)
+
+// useTempConfig points the user config directory, where the settings are
+// kept, to a directory of the test and returns the settings file's path
+func useTempConfig(t *testing.T) string {
+	dir := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", dir)
+	t.Setenv("HOME", dir)
+	t.Setenv("AppData", dir)
+
+	path, err := settingsPath()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestSanitize(t *testing.T) {
+	s := &Settings{
+		choices: choices{
+			Size:        sim.NbSize,
+			Rows:        2,
+			Cols:        100,
+			Color:       -1,
+			Mode:        NbPlayModes,
+			MusicVolume: 3,
+			SoundVolume: -1,
+		},
+		Layout: NbLayouts,
+		Keys:   Bindings{"up1": {}},
+	}
+	s.sanitize()
+
+	def := DefaultSettings()
+	if s.Version != settingsVersion || s.Size != def.Size || s.Color != def.Color || s.Mode != def.Mode || s.Layout != def.Layout {
+		t.Errorf("settings out of range left as %+v", s)
+	}
+	if s.Rows != sim.MinSide || s.Cols != sim.MaxSide {
+		t.Errorf("custom board of %dx%d, want %dx%d", s.Cols, s.Rows, sim.MaxSide, sim.MinSide)
+	}
+	if s.MusicVolume != 1 || s.SoundVolume != 0 {
+		t.Errorf("volumes %v and %v, want 1 and 0", s.MusicVolume, s.SoundVolume)
+	}
+	if s.WindowWidth != def.WindowWidth || s.WindowHeight != def.WindowHeight {
+		t.Errorf("window of %dx%d, want the default one", s.WindowWidth, s.WindowHeight)
+	}
+	for name, keys := range def.Keys {
+		if len(s.Keys[name]) == 0 {
+			t.Errorf("no keys for %s, want the default ones %v", name, keys)
+		}
+	}
+}
+
+func TestSettingsKeepUnknownFields(t *testing.T) {
+	path := useTempConfig(t)
+	file := `{"version": 9, "size": 1, "future": {"theme": "dark"}, "keys": {"up1": ["ArrowUp"], "dash": ["Q"]}}`
+	if err := os.WriteFile(path, []byte(file), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	s := LoadSettings()
+	if s.Version != 9 || s.Size != sim.Medium {
+		t.Errorf("loaded version %d and size %v, want 9 and %v", s.Version, s.Size, sim.Medium)
+	}
+	s.Color = sim.Red
+	s.save()
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var saved struct {
+		Color  sim.Color                  `json:"color"`
+		Future map[string]string          `json:"future"`
+		Keys   map[string]json.RawMessage `json:"keys"`
+	}
+	if err := json.Unmarshal(data, &saved); err != nil {
+		t.Fatal(err)
+	}
+	if saved.Color != sim.Red {
+		t.Errorf("saved color %v, want %v", saved.Color, sim.Red)
+	}
+	if saved.Future["theme"] != "dark" {
+		t.Errorf("lost the unknown field, saved %s", data)
+	}
+	if _, ok := saved.Keys["dash"]; !ok {
+		t.Errorf("lost the keys of an unknown action, saved %s", data)
+	}
+}
@@ -2,12 +2,11 @@ package game
 
 import (
 	"image"
-	"math/rand"
 	"time"
 
 	"github.com/adan-ea/GoSnakeGo/constants"
-	"github.com/adan-ea/GoSnakeGo/resources/audio"
 	"github.com/adan-ea/GoSnakeGo/resources/images"
+	"github.com/adan-ea/GoSnakeGo/sim"
 	"github.com/hajimehoshi/ebiten/v2"
 )
 
@@ -22,133 +21,52 @@ const (
 	frameDelay   = 50 // Delay between frames
 )
 
-// Snake represents the snake
-type Snake struct {
-	body             []Point
-	direction        Direction
-	color            Color
-	justAte          bool
-	changedDirection bool
-	currentFrame     int
-	lastFrameTime    time.Time
+// snakeSprite draws a snake and keeps track of its animation
+type snakeSprite struct {
+	currentFrame  int
+	lastFrameTime time.Time
 }
 
-// newSnake creates a new snake with the given body and direction
-func newSnake(color Color) *Snake {
-	if color == RandomColor {
-		color = Color(rand.Intn(nbColors - 1))
-	}
-	return &Snake{
-		body: []Point{
-			{x: 1, y: 1},
-			{x: 2, y: 1},
-			{x: 3, y: 1}},
-		color:         color,
+func newSnakeSprite() *snakeSprite {
+	return &snakeSprite{
 		lastFrameTime: time.Now(),
 	}
 }
 
-// Returns the position of the snake's head
-func (s *Snake) Head() Point {
-	return s.body[len(s.body)-1]
-}
-
-func (s *Snake) changeDirection(newDir Direction) {
-	opposites := map[Direction]Direction{
-		Up:    Down,
-		Right: Left,
-		Down:  Up,
-		Left:  Right,
-	}
-	if !s.changedDirection {
-		// Prevent the snake from reversing direction
-		if o, ok := opposites[newDir]; ok && o != s.direction {
-			s.direction = newDir
-			s.changedDirection = true
-		}
-	}
-}
-
-// headHits checks if the snake's head is at the given position
-func (s *Snake) headHits(x, y int) bool {
-	h := s.Head()
-
-	return h.x == x && h.y == y
-}
-
-func (s *Snake) headHitsBody() bool {
-	h := s.Head()
-	bodyWithoutHead := s.body[:len(s.body)-1]
-
-	for _, b := range bodyWithoutHead {
-		if b.x == h.x && b.y == h.y {
-			return true
-		}
-	}
-
-	return false
-}
-
-// move moves the snake one step in its current direction
-func (s *Snake) move() {
-	s.changedDirection = false
-	// Create a copy of the head position to avoid modifying the original directly
-	h := s.Head()
-	newHead := Point{x: h.x, y: h.y}
-	// Calculate the new position of the head based on the direction
-	switch s.direction {
-	case Up:
-		newHead.y--
-	case Down:
-		newHead.y++
-	case Left:
-		newHead.x--
-	case Right:
-		newHead.x++
-	}
-
-	if s.justAte {
-		audio.PlayOnce(audio.EatPlayer)
-		s.body = append(s.body, newHead)
-		s.justAte = false
-	} else {
-		s.body = append(s.body[1:], newHead)
-	}
-}
-
 // Draw draws the snake onto the screen
-func (s *Snake) Draw(screen *ebiten.Image, offsetX, offsetY int) {
+func (s *snakeSprite) Draw(screen *ebiten.Image, snake *sim.Snake, offsetX, offsetY int) {
 	s.updateAnimation()
+	body := snake.Body()
 	// Draw the snake's body and tail first
-	for i := 0; i < len(s.body); i++ {
-		part := s.body[i]
-		sx := float64(offsetX + part.x*constants.TileSize)
-		sy := float64(offsetY + part.y*constants.TileSize)
+	for i := 0; i < len(body); i++ {
+		part := body[i]
+		sx := float64(offsetX + part.X*constants.TileSize)
+		sy := float64(offsetY + part.Y*constants.TileSize)
 
 		switch {
 		case i == 0:
-			s.handleTail(screen, sx, sy, i)
-		case i == len(s.body)-1:
-			s.handleHead(screen, sx, sy)
+			s.handleTail(screen, snake, sx, sy, i)
+		case i == len(body)-1:
+			s.handleHead(screen, snake, sx, sy)
 		default:
-			s.handleBody(screen, sx, sy, i)
+			s.handleBody(screen, snake, sx, sy, i)
 		}
 	}
 }
 
 // handleHead draws the snake's head
-func (s *Snake) handleHead(screen *ebiten.Image, sx, sy float64) {
+func (s *snakeSprite) handleHead(screen *ebiten.Image, snake *sim.Snake, sx, sy float64) {
 	frameX := s.currentFrame * headWidth
 	var frameY int
 
-	switch s.direction {
-	case Up:
+	switch snake.Direction() {
+	case sim.Up:
 		frameY = 1 * headHeight
-	case Down:
+	case sim.Down:
 		frameY = 2 * headHeight
-	case Left:
+	case sim.Left:
 		frameY = 3 * headHeight
-	case Right:
+	case sim.Right:
 		frameY = 0 * headHeight
 	}
 
@@ -159,35 +77,37 @@ func (s *Snake) handleHead(screen *ebiten.Image, sx, sy float64) {
 }
 
 // handleBody draws the snake's body
-func (s *Snake) handleBody(screen *ebiten.Image, sx, sy float64, i int) {
-	prev := s.body[i-1]
-	curr := s.body[i]
-	next := s.body[i+1]
+func (s *snakeSprite) handleBody(screen *ebiten.Image, snake *sim.Snake, sx, sy float64, i int) {
+	body := snake.Body()
+	curr := body[i]
+	prev := neighbour(curr, body[i-1])
+	next := neighbour(curr, body[i+1])
+	color := int(snake.Color())
 
 	var bodyImage *ebiten.Image
 	switch {
 	// Vertical
-	case prev.x == next.x:
-		bodyImage = images.BodySprite[int(s.color)].SubImage(image.Rect(frameWidth, 0, 2*frameWidth, frameHeight)).(*ebiten.Image)
+	case prev.X == next.X:
+		bodyImage = images.BodySprite[color].SubImage(image.Rect(frameWidth, 0, 2*frameWidth, frameHeight)).(*ebiten.Image)
 	// Horizontal
-	case prev.y == next.y:
-		bodyImage = images.BodySprite[int(s.color)].SubImage(image.Rect(0, 0, frameWidth, frameHeight)).(*ebiten.Image)
+	case prev.Y == next.Y:
+		bodyImage = images.BodySprite[color].SubImage(image.Rect(0, 0, frameWidth, frameHeight)).(*ebiten.Image)
 
 	// Top left corner
-	case (prev.x > curr.x && next.y < curr.y) || (next.x > curr.x && prev.y < curr.y):
-		bodyImage = images.BodySprite[int(s.color)].SubImage(image.Rect(4*frameWidth, 0, 5*frameWidth, frameHeight)).(*ebiten.Image)
+	case (prev.X > curr.X && next.Y < curr.Y) || (next.X > curr.X && prev.Y < curr.Y):
+		bodyImage = images.BodySprite[color].SubImage(image.Rect(4*frameWidth, 0, 5*frameWidth, frameHeight)).(*ebiten.Image)
 
 	// Top right corner
-	case (prev.x < curr.x && next.y < curr.y) || (next.x < curr.x && prev.y < curr.y):
-		bodyImage = images.BodySprite[int(s.color)].SubImage(image.Rect(5*frameWidth, 0, 6*frameWidth, frameHeight)).(*ebiten.Image)
+	case (prev.X < curr.X && next.Y < curr.Y) || (next.X < curr.X && prev.Y < curr.Y):
+		bodyImage = images.BodySprite[color].SubImage(image.Rect(5*frameWidth, 0, 6*frameWidth, frameHeight)).(*ebiten.Image)
 
 	// Bottom right corner
-	case (prev.x < curr.x && next.y > curr.y) || (next.x < curr.x && prev.y > curr.y):
-		bodyImage = images.BodySprite[int(s.color)].SubImage(image.Rect(3*frameWidth, 0, 4*frameWidth, frameHeight)).(*ebiten.Image)
+	case (prev.X < curr.X && next.Y > curr.Y) || (next.X < curr.X && prev.Y > curr.Y):
+		bodyImage = images.BodySprite[color].SubImage(image.Rect(3*frameWidth, 0, 4*frameWidth, frameHeight)).(*ebiten.Image)
 
 	// Bottom left corner
-	case (prev.x > curr.x && next.y > curr.y) || (next.x > curr.x && prev.y > curr.y):
-		bodyImage = images.BodySprite[int(s.color)].SubImage(image.Rect(2*frameWidth, 0, 3*frameWidth, frameHeight)).(*ebiten.Image)
+	case (prev.X > curr.X && next.Y > curr.Y) || (next.X > curr.X && prev.Y > curr.Y):
+		bodyImage = images.BodySprite[color].SubImage(image.Rect(2*frameWidth, 0, 3*frameWidth, frameHeight)).(*ebiten.Image)
 	}
 
 	bodyOp := &ebiten.DrawImageOptions{}
@@ -196,20 +116,22 @@ func (s *Snake) handleBody(screen *ebiten.Image, sx, sy float64, i int) {
 }
 
 // handleTail draws the snake's tail
-func (s *Snake) handleTail(screen *ebiten.Image, sx, sy float64, i int) {
-	tail := s.body[i]
-	prev := s.body[i+1]
+func (s *snakeSprite) handleTail(screen *ebiten.Image, snake *sim.Snake, sx, sy float64, i int) {
+	body := snake.Body()
+	tail := body[i]
+	prev := neighbour(tail, body[i+1])
+	color := int(snake.Color())
 
 	var tailImage *ebiten.Image
 	switch {
-	case tail.x > prev.x: // Going left
-		tailImage = images.TailSprite[int(s.color)].SubImage(image.Rect(3*frameWidth, 0, 4*frameWidth, frameHeight)).(*ebiten.Image)
-	case tail.x < prev.x: // Going right
-		tailImage = images.TailSprite[int(s.color)].SubImage(image.Rect(0, 0, frameWidth, frameHeight)).(*ebiten.Image)
-	case tail.y > prev.y: // Going up
-		tailImage = images.TailSprite[int(s.color)].SubImage(image.Rect(2*frameWidth, 0, 3*frameWidth, frameHeight)).(*ebiten.Image)
-	case tail.y < prev.y: // Going down
-		tailImage = images.TailSprite[int(s.color)].SubImage(image.Rect(frameWidth, 0, 2*frameWidth, frameHeight)).(*ebiten.Image)
+	case tail.X > prev.X: // Going left
+		tailImage = images.TailSprite[color].SubImage(image.Rect(3*frameWidth, 0, 4*frameWidth, frameHeight)).(*ebiten.Image)
+	case tail.X < prev.X: // Going right
+		tailImage = images.TailSprite[color].SubImage(image.Rect(0, 0, frameWidth, frameHeight)).(*ebiten.Image)
+	case tail.Y > prev.Y: // Going up
+		tailImage = images.TailSprite[color].SubImage(image.Rect(2*frameWidth, 0, 3*frameWidth, frameHeight)).(*ebiten.Image)
+	case tail.Y < prev.Y: // Going down
+		tailImage = images.TailSprite[color].SubImage(image.Rect(frameWidth, 0, 2*frameWidth, frameHeight)).(*ebiten.Image)
 	}
 
 	tailOp := &ebiten.DrawImageOptions{}
@@ -217,8 +139,27 @@ func (s *Snake) handleTail(screen *ebiten.Image, sx, sy float64, i int) {
 	screen.DrawImage(tailImage, tailOp)
 }
 
+// neighbour returns the position of the segment next to curr as if both were
+// on the same side of the board. Segments that are further apart wrapped
+// around the edge, so the neighbour is on the other side of curr.
+func neighbour(curr, next sim.Point) sim.Point {
+	switch {
+	case next.X-curr.X > 1:
+		next.X = curr.X - 1
+	case curr.X-next.X > 1:
+		next.X = curr.X + 1
+	}
+	switch {
+	case next.Y-curr.Y > 1:
+		next.Y = curr.Y - 1
+	case curr.Y-next.Y > 1:
+		next.Y = curr.Y + 1
+	}
+	return next
+}
+
 // updateAnimation updates the snake's animation frame
-func (s *Snake) updateAnimation() {
+func (s *snakeSprite) updateAnimation() {
 	if time.Since(s.lastFrameTime) >= time.Millisecond*frameDelay {
 		s.currentFrame = (s.currentFrame + 1) % frameCount
 		s.lastFrameTime = time.Now()
@@ -0,0 +1,53 @@
+package game
+
+import (
+	"fmt"
+	"image/color"
+
+	"github.com/adan-ea/GoSnakeGo/constants"
+	"github.com/adan-ea/GoSnakeGo/resources/fonts"
+	"github.com/adan-ea/GoSnakeGo/sim"
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/text"
+	"golang.org/x/image/font"
+)
+
+const (
+	// Seconds added to the clock for each food eaten in time attack
+	timeAttackBonus = 3
+	// Seconds left under which the countdown turns red
+	timeAttackHurry = 10
+)
+
+// timeAttack returns the time limit of the given play mode, nil when it has none
+func timeAttack(mode PlayMode) *sim.TimeAttack {
+	var budget int
+	switch mode {
+	case PlayTimeAttack60:
+		budget = 60
+	case PlayTimeAttack120:
+		budget = 120
+	default:
+		return nil
+	}
+
+	return &sim.TimeAttack{
+		Budget: budget * sim.TicksPerSecond,
+		Bonus:  timeAttackBonus * sim.TicksPerSecond,
+	}
+}
+
+// drawCountdown renders the time left at the top of the screen
+func (b *Board) drawCountdown(screen *ebiten.Image) {
+	// round up so the countdown shows 0:00 only once the time is up
+	seconds := (b.state.TimeLeft() + sim.TicksPerSecond - 1) / sim.TicksPerSecond
+	countdownText := fmt.Sprintf("%d:%02d", seconds/60, seconds%60)
+
+	var textColor color.Color = color.White
+	if seconds <= timeAttackHurry {
+		textColor = constants.Red
+	}
+
+	countdownX := (constants.ScreenWidth - font.MeasureString(fonts.RegularFont, countdownText).Round()) / 2
+	text.Draw(screen, countdownText, fonts.RegularFont, countdownX, 30, textColor)
+}
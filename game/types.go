@@ -1,6 +1,11 @@
 package game
 
-import "math/rand"
+import (
+	"fmt"
+	"strings"
+
+	"github.com/adan-ea/GoSnakeGo/sim"
+)
 
 // Mode represents the game mode
 type Mode int
@@ -9,116 +14,81 @@ const (
 	ModeTitle Mode = iota
 	ModeGame
 	ModeGameOver
+	ModeReplay
+	ModePause
+	ModeSettings
+	ModeStages
+	ModeDemo
+	ModeControls
+	ModeGamepads
 )
 
-// Point represents a point in 2D space
-type Point struct {
-	x, y int
-}
-
-// Size represents the size of the board
-type Size int
+// PlayMode represents the rules the player picked on the title screen
+type PlayMode int
 
-const nbSize = 5
 const (
-	Small Size = iota
-	Medium
-	Large
-	ExtraLarge
-	RandomSize
+	PlayClassic PlayMode = iota
+	PlayTimeAttack60
+	PlayTimeAttack120
+	PlayVersus
+	NbPlayModes
 )
 
-func getGridSize(size Size) (int, int) {
-	if size == RandomSize {
-		size = Size(rand.Intn(nbSize - 1))
+// ParsePlayMode returns the mode with the given name: classic, timeattack60,
+// timeattack120 or versus
+func ParsePlayMode(name string) (PlayMode, error) {
+	switch strings.ToLower(name) {
+	case "classic":
+		return PlayClassic, nil
+	case "timeattack60":
+		return PlayTimeAttack60, nil
+	case "timeattack120":
+		return PlayTimeAttack120, nil
+	case "versus":
+		return PlayVersus, nil
 	}
-	switch size {
-	case Small:
-		return 14, 14
-	case Medium:
-		return 16, 16
-	case Large:
-		return 18, 18
-	case ExtraLarge:
-		return 20, 20
-	}
-	return 18, 18
+	return 0, fmt.Errorf("unknown mode %q", name)
 }
 
-func getSizeText(size Size) string {
-	switch size {
-	case Small:
-		return "Small"
-	case Medium:
-		return "Medium"
-	case Large:
-		return "Large"
-	case ExtraLarge:
-		return "Extra Large"
-	case RandomSize:
+func getColorText(color sim.Color) string {
+	switch color {
+	case sim.Blue:
+		return "Blue"
+	case sim.Purple:
+		return "Purple"
+	case sim.Red:
+		return "Red"
+	case sim.RandomColor:
 		return "Random"
 	}
-
-	return "Large"
+	return "Blue"
 }
 
-func getTextToSize(text string) Size {
-	switch text {
-	case "Small":
-		return Small
-	case "Medium":
-		return Medium
-	case "Large":
-		return Large
-	case "Extra Large":
-		return ExtraLarge
+func getWallsText(walls sim.Walls) string {
+	switch walls {
+	case sim.SolidWalls:
+		return "Solid"
+	case sim.WrapWalls:
+		return "Wrap"
 	}
-	return Large
+	return "Solid"
 }
 
-func getSizeFromRowsCols(rows, cols int) Size {
-	if rows == 14 && cols == 14 {
-		return Small
-	} else if rows == 16 && cols == 16 {
-		return Medium
-	} else if rows == 18 && cols == 18 {
-		return Large
+func getOnOffText(on bool) string {
+	if on {
+		return "On"
 	}
-	return ExtraLarge
+	return "Off"
 }
 
-// Direction represents the direction of the snake's movement
-type Direction int
-
-const (
-	Right Direction = iota
-	Left
-	Down
-	Up
-)
-
-// Color represents possible colors for the snake
-type Color int
-
-// Number of colors available including Random
-const nbColors = 4
-const (
-	Blue Color = iota
-	Purple
-	Red
-	RandomColor
-)
-
-func getColorText(color Color) string {
-	switch color {
-	case Blue:
-		return "Blue"
-	case Purple:
-		return "Purple"
-	case Red:
-		return "Red"
-	case RandomColor:
-		return "Random"
+func getPlayModeText(mode PlayMode) string {
+	switch mode {
+	case PlayTimeAttack60:
+		return "Time Attack 60s"
+	case PlayTimeAttack120:
+		return "Time Attack 120s"
+	case PlayVersus:
+		return "Versus"
 	}
-	return "Blue"
+	return "Classic"
 }
@@ -0,0 +1,84 @@
+package game
+
+import (
+	"fmt"
+	"image"
+
+	"github.com/adan-ea/GoSnakeGo/resources/images"
+	"github.com/adan-ea/GoSnakeGo/sim"
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// Rounds a player has to win to win a versus match
+const roundsToWin = 3
+
+// match counts the rounds won by each player of a versus match
+type match struct {
+	Wins [2]int `json:"wins"`
+}
+
+// record counts the round that just ended on the board
+func (m *match) record(b *sim.Board) {
+	if winner := b.Winner(); winner >= 0 && winner < len(m.Wins) {
+		m.Wins[winner]++
+	}
+}
+
+// winner returns the index of the player who won the match, -1 while it goes on
+func (m *match) winner() int {
+	for i, wins := range m.Wins {
+		if wins >= roundsToWin {
+			return i
+		}
+	}
+	return -1
+}
+
+// versusSnakes returns the snakes of a versus board, the second player gets
+// the color after the first player's
+func versusSnakes(color sim.Color) []sim.SnakeConfig {
+	second := color
+	if color != sim.RandomColor {
+		second = (color + 1) % (sim.NbColors - 1)
+	}
+	return []sim.SnakeConfig{{Color: color}, {Color: second}}
+}
+
+// versus reports whether two players share the board
+func versus(b *sim.Board) bool {
+	players := 0
+	for _, s := range b.Snakes() {
+		if !s.Bot() {
+			players++
+		}
+	}
+	return players == 2
+}
+
+// drawVersusScores renders the score of each player next to a piece of their snake
+func (b *Board) drawVersusScores(screen *ebiten.Image) {
+	snakes := b.state.Snakes()
+	b.DrawScoreWithSprite(screen, snakeIcon(snakes[0]), snakes[0].Score(), 0, 7)
+	b.DrawScoreWithSprite(screen, snakeIcon(snakes[1]), snakes[1].Score(), 550, 7)
+}
+
+// snakeIcon returns a straight body segment in the color of the snake
+func snakeIcon(s *sim.Snake) *ebiten.Image {
+	return images.BodySprite[int(s.Color())].SubImage(image.Rect(0, 0, frameWidth, frameHeight)).(*ebiten.Image)
+}
+
+// roundText returns the result of the round that just ended on the board
+func roundText(b *sim.Board) string {
+	if winner := b.Winner(); winner >= 0 {
+		return fmt.Sprintf("Player %d Wins", winner+1)
+	}
+	return "Draw"
+}
+
+// text returns the score of the match
+func (m *match) text() string {
+	if winner := m.winner(); winner >= 0 {
+		return fmt.Sprintf("Player %d wins the match %d - %d", winner+1, m.Wins[0], m.Wins[1])
+	}
+	return fmt.Sprintf("Rounds: %d - %d", m.Wins[0], m.Wins[1])
+}
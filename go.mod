@@ -5,6 +5,8 @@ go 1.22.2
 require (
 	github.com/hajimehoshi/ebiten/v2 v2.7.4
 	golang.org/x/image v0.16.0
+	golang.org/x/sys v0.20.0
+	golang.org/x/term v0.19.0
 )
 
 require (
@@ -16,6 +18,5 @@ require (
 	github.com/jfreymuth/oggvorbis v1.0.5 // indirect
 	github.com/jfreymuth/vorbis v1.0.2 // indirect
 	golang.org/x/sync v0.7.0 // indirect
-	golang.org/x/sys v0.20.0 // indirect
 	golang.org/x/text v0.15.0 // indirect
 )
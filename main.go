@@ -1,28 +1,80 @@
 package main
 
 import (
-	"image"
+	"fmt"
 	"log"
+	"os"
+	"runtime/debug"
+	"strings"
 
-	"github.com/adan-ea/GoSnakeGo/constants"
-	"github.com/adan-ea/GoSnakeGo/game"
-	"github.com/adan-ea/GoSnakeGo/resources/audio"
-	"github.com/adan-ea/GoSnakeGo/resources/fonts"
-	"github.com/adan-ea/GoSnakeGo/resources/images"
-	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/adan-ea/GoSnakeGo/env"
+	"github.com/adan-ea/GoSnakeGo/sim"
 )
 
-func main() {
-	images.InitImages()
-	audio.InitAudio()
-	fonts.InitFonts()
+// version of the game, set when building a release with
+// -ldflags "-X main.version=1.2.0"
+var version = "dev"
+
+const usage = `usage: gosnakego [command] [flags]
+
+commands:
+  play     play in a window, the default command of builds with one
+  replay   watch a replay in a window
+  scores   print the high scores
+  tui      play in the terminal, the default command of headless builds
+  arena    play a game between Battlesnake servers
+  env      run the game headless for training agents
+  version  print the version
 
-	g := game.NewGame()
+Run gosnakego <command> -h to see the flags of a command.
+`
+
+func main() {
+	args := os.Args[1:]
+	cmd := defaultCommand
+	if len(args) > 0 && !strings.HasPrefix(args[0], "-") {
+		cmd, args = args[0], args[1:]
+	}
 
-	ebiten.SetWindowSize(constants.ScreenWidth, constants.ScreenHeight)
-	ebiten.SetWindowIcon([]image.Image{images.IconSprite})
-	ebiten.SetWindowTitle("Go Snake Go!")
-	if err := ebiten.RunGame(g); err != nil {
+	// only play and replay open a window, the other commands run headless and
+	// still work in a build made with -tags headless
+	var err error
+	switch cmd {
+	case "play":
+		err = runPlay(args)
+	case "replay":
+		err = runReplay(args)
+	case "scores":
+		err = runScores(args)
+	case "tui":
+		err = runTUI(args)
+	case "arena":
+		err = runArena(args)
+	case "env":
+		err = env.Serve(os.Stdin, os.Stdout)
+	case "version":
+		printVersion()
+	case "help":
+		fmt.Print(usage)
+	default:
+		fmt.Fprintf(os.Stderr, "unknown command %q\n\n%s", cmd, usage)
+		os.Exit(2)
+	}
+	if err != nil {
 		log.Fatal(err)
 	}
 }
+
+// printVersion prints the version of the game and of its rules, with the
+// commit it was built from when it is known
+func printVersion() {
+	v := version
+	if info, ok := debug.ReadBuildInfo(); ok {
+		for _, s := range info.Settings {
+			if s.Key == "vcs.revision" && len(s.Value) >= 7 {
+				v += " (" + s.Value[:7] + ")"
+			}
+		}
+	}
+	fmt.Printf("gosnakego %s, rules version %d\n", v, sim.RulesVersion)
+}
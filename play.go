@@ -0,0 +1,117 @@
+//go:build !headless
+
+package main
+
+import (
+	"flag"
+	"fmt"
+	"image"
+	"os"
+
+	"github.com/adan-ea/GoSnakeGo/game"
+	"github.com/adan-ea/GoSnakeGo/replay"
+	"github.com/adan-ea/GoSnakeGo/resources/audio"
+	"github.com/adan-ea/GoSnakeGo/resources/fonts"
+	"github.com/adan-ea/GoSnakeGo/resources/images"
+	"github.com/adan-ea/GoSnakeGo/resources/levels"
+	"github.com/adan-ea/GoSnakeGo/sim"
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// defaultCommand runs when no command is given
+const defaultCommand = "play"
+
+// runPlay opens the game window with the options given as arguments picked
+// on the title screen
+func runPlay(args []string) error {
+	fs := flag.NewFlagSet("play", flag.ExitOnError)
+	// the sizes, colors and modes default to the ones last picked
+	size := fs.String("size", "", "board size: small, medium, large, extralarge, random or WIDTHxHEIGHT")
+	color := fs.String("color", "", "snake color: blue, purple, red or random")
+	seed := fs.Uint64("seed", 0, "seed of every game, random when not set")
+	mode := fs.String("mode", "", "game mode: classic, timeattack60, timeattack120 or versus")
+	fullscreen := fs.Bool("fullscreen", false, "start in fullscreen, or in a window with -fullscreen=false")
+	mute := fs.Bool("mute", false, "turn the music and the sounds off")
+	fs.Usage = func() {
+		fmt.Fprint(fs.Output(), usage, "\nflags of play:\n")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+
+	// the flags given override the settings the game was left with
+	opts := game.LoadSettings().Options()
+	opts.Seed, opts.Mute = *seed, *mute
+	var err error
+	fs.Visit(func(f *flag.Flag) {
+		if err != nil {
+			return
+		}
+		switch f.Name {
+		case "size":
+			var board sim.Config
+			err = board.SetSize(*size)
+			opts.Size, opts.Rows, opts.Cols = board.Size, board.Rows, board.Cols
+		case "color":
+			opts.Color, err = sim.ParseColor(*color)
+		case "mode":
+			opts.Mode, err = game.ParsePlayMode(*mode)
+		case "fullscreen":
+			opts.Fullscreen = *fullscreen
+		case "seed":
+			opts.HasSeed = true
+		}
+	})
+	if err != nil {
+		return err
+	}
+
+	return runWindow(opts)
+}
+
+// runReplay opens the replay file given as argument in the game window
+func runReplay(args []string) error {
+	fs := flag.NewFlagSet("replay", flag.ExitOnError)
+	fullscreen := fs.Bool("fullscreen", false, "start in fullscreen")
+	mute := fs.Bool("mute", false, "turn the sounds off")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "usage: gosnakego replay [flags] file"+replay.Extension)
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+	if fs.NArg() != 1 {
+		fs.Usage()
+		os.Exit(2)
+	}
+
+	r, err := replay.Load(fs.Arg(0))
+	if err != nil {
+		return err
+	}
+	opts := game.LoadSettings().Options()
+	opts.Replay, opts.Mute = r, *mute
+	fs.Visit(func(f *flag.Flag) {
+		if f.Name == "fullscreen" {
+			opts.Fullscreen = *fullscreen
+		}
+	})
+	return runWindow(opts)
+}
+
+// runWindow loads the resources of the game and runs it in a window
+func runWindow(opts game.Options) error {
+	images.InitImages()
+	audio.InitAudio()
+	fonts.InitFonts()
+	levels.InitLevels()
+
+	g := game.NewGame(opts)
+
+	ebiten.SetWindowSize(opts.Settings.WindowWidth, opts.Settings.WindowHeight)
+	ebiten.SetWindowResizingMode(ebiten.WindowResizingModeEnabled)
+	ebiten.SetWindowIcon([]image.Image{images.IconSprite})
+	ebiten.SetWindowTitle("Go Snake Go!")
+	// the game saves itself when the window is closed or loses focus
+	ebiten.SetWindowClosingHandled(true)
+	ebiten.SetRunnableOnUnfocused(true)
+	return ebiten.RunGame(g)
+}
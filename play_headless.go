@@ -0,0 +1,21 @@
+//go:build headless
+
+package main
+
+import "errors"
+
+// defaultCommand runs when no command is given, builds without a window play
+// in the terminal
+const defaultCommand = "tui"
+
+// errHeadless is returned by the commands that need a window in a build
+// made with -tags headless, which leaves Ebiten and the audio libraries out
+var errHeadless = errors.New("this build has no window, build it without -tags headless to play in one")
+
+func runPlay(args []string) error {
+	return errHeadless
+}
+
+func runReplay(args []string) error {
+	return errHeadless
+}
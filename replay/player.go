@@ -0,0 +1,71 @@
+package replay
+
+import "github.com/adan-ea/GoSnakeGo/sim"
+
+// Player plays a replay back tick by tick
+type Player struct {
+	replay  *Replay
+	board   *sim.Board
+	scripts []sim.Controller // inputs of each snake
+}
+
+// NewPlayer creates a player positioned at the start of the replay
+func NewPlayer(r *Replay) *Player {
+	p := &Player{replay: r}
+	p.reset()
+
+	return p
+}
+
+func (p *Player) reset() {
+	p.board = sim.NewBoard(p.replay.Config)
+
+	turns := make([][]sim.Turn, len(p.board.Snakes()))
+	for _, in := range p.replay.Inputs {
+		if in.Snake < len(turns) {
+			turns[in.Snake] = append(turns[in.Snake], sim.Turn{Tick: in.Tick, Dir: in.Dir})
+		}
+	}
+
+	p.scripts = make([]sim.Controller, len(turns))
+	for i := range turns {
+		p.scripts[i] = sim.NewScript(turns[i])
+	}
+}
+
+// Board returns the board being played. It changes when seeking backwards.
+func (p *Player) Board() *sim.Board {
+	return p.board
+}
+
+// Replay returns the replay being played
+func (p *Player) Replay() *Replay {
+	return p.replay
+}
+
+// Done reports whether the whole replay has been played
+func (p *Player) Done() bool {
+	return p.board.GameOver() || p.board.Ticks() >= p.replay.Length
+}
+
+// Step plays the next tick of the replay
+func (p *Player) Step() []sim.Event {
+	if p.Done() {
+		return nil
+	}
+
+	_, events := p.board.TickWith(p.scripts)
+	return events
+}
+
+// Seek moves the replay to the given tick, playing it again from the start
+// when going backwards
+func (p *Player) Seek(tick int) {
+	if tick < p.board.Ticks() {
+		p.reset()
+	}
+
+	for p.board.Ticks() < tick && !p.Done() {
+		p.Step()
+	}
+}
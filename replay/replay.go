@@ -0,0 +1,207 @@
+// Package replay records the inputs of a game so it can be played back exactly
+package replay
+
+import (
+	"bufio"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/adan-ea/GoSnakeGo/sim"
+)
+
+// Magic bytes at the start of every replay file
+const magic = "GSGR"
+
+// Extension of replay files
+const Extension = ".gsr"
+
+// Input is a direction pressed on a given tick
+type Input struct {
+	Tick  int           `json:"tick"`  // tick on which the direction was pressed, starting at 1
+	Snake int           `json:"snake"` // index of the snake the direction was given to
+	Dir   sim.Direction `json:"dir"`
+}
+
+// The direction and the snake of an input share a byte, the direction
+// taking the lowest bits
+const (
+	dirBits  = 2
+	dirMask  = 1<<dirBits - 1
+	maxSnake = 1<<(8-dirBits) - 1
+)
+
+// Limits of what Read accepts so a damaged file is an error instead of a
+// huge allocation
+const (
+	maxConfigSize = 64 << 10
+	maxLength     = 1 << 31 // ticks, more than a year of play
+)
+
+// Replay holds everything needed to play a game again
+type Replay struct {
+	Version int        `json:"version"` // rules version the game was played with
+	Config  sim.Config `json:"config"`  // config the board was created with, including the seed
+	Length  int        `json:"length"`  // number of ticks played
+	Inputs  []Input    `json:"inputs"`
+}
+
+// New creates an empty replay for a board created with the given config
+func New(cfg sim.Config) *Replay {
+	return &Replay{
+		Version: sim.RulesVersion,
+		Config:  cfg,
+	}
+}
+
+// Record adds the actions given to each snake on the given tick to the replay
+func (r *Replay) Record(tick int, actions ...sim.Action) {
+	if tick > r.Length {
+		r.Length = tick
+	}
+	for i, action := range actions {
+		if action.Turn && i <= maxSnake {
+			r.Inputs = append(r.Inputs, Input{Tick: tick, Snake: i, Dir: action.Dir})
+		}
+	}
+}
+
+// Write encodes the replay to w. The config is stored as JSON so new options
+// do not change the layout, and ticks are stored as deltas from the previous
+// input so a whole game takes a few hundred bytes.
+func (r *Replay) Write(w io.Writer) error {
+	cfg, err := json.Marshal(r.Config)
+	if err != nil {
+		return err
+	}
+
+	bw := bufio.NewWriter(w)
+	buf := make([]byte, binary.MaxVarintLen64)
+	putUvarint := func(v uint64) {
+		n := binary.PutUvarint(buf, v)
+		bw.Write(buf[:n])
+	}
+
+	bw.WriteString(magic)
+	putUvarint(uint64(r.Version))
+	putUvarint(uint64(len(cfg)))
+	bw.Write(cfg)
+	putUvarint(uint64(r.Length))
+	putUvarint(uint64(len(r.Inputs)))
+
+	last := 0
+	for _, in := range r.Inputs {
+		putUvarint(uint64(in.Tick - last))
+		bw.WriteByte(byte(in.Snake<<dirBits | int(in.Dir)&dirMask))
+		last = in.Tick
+	}
+
+	return bw.Flush()
+}
+
+// Read decodes a replay written by Write
+func Read(r io.Reader) (*Replay, error) {
+	br := bufio.NewReader(r)
+
+	header := make([]byte, len(magic))
+	if _, err := io.ReadFull(br, header); err != nil {
+		return nil, err
+	}
+	if string(header) != magic {
+		return nil, errors.New("not a replay file")
+	}
+
+	version, err := binary.ReadUvarint(br)
+	if err != nil {
+		return nil, err
+	}
+	if version != sim.RulesVersion {
+		return nil, fmt.Errorf("replay uses rules version %d, this game uses version %d", version, sim.RulesVersion)
+	}
+
+	cfgLen, err := binary.ReadUvarint(br)
+	if err != nil {
+		return nil, err
+	}
+	if cfgLen > maxConfigSize {
+		return nil, fmt.Errorf("replay config of %d bytes is too large", cfgLen)
+	}
+	cfg := make([]byte, cfgLen)
+	if _, err := io.ReadFull(br, cfg); err != nil {
+		return nil, err
+	}
+
+	rep := &Replay{Version: int(version)}
+	if err := json.Unmarshal(cfg, &rep.Config); err != nil {
+		return nil, err
+	}
+
+	length, err := binary.ReadUvarint(br)
+	if err != nil {
+		return nil, err
+	}
+	if length > maxLength {
+		return nil, fmt.Errorf("replay of %d ticks is too long", length)
+	}
+	rep.Length = int(length)
+
+	// every snake turns at most once per tick
+	count, err := binary.ReadUvarint(br)
+	if err != nil {
+		return nil, err
+	}
+	if count > length*(maxSnake+1) {
+		return nil, fmt.Errorf("replay of %d ticks can't have %d inputs", length, count)
+	}
+	last := 0
+	for i := uint64(0); i < count; i++ {
+		delta, err := binary.ReadUvarint(br)
+		if err != nil {
+			return nil, err
+		}
+		b, err := br.ReadByte()
+		if err != nil {
+			return nil, err
+		}
+
+		if delta > length-uint64(last) {
+			return nil, errors.New("replay input after the end of the game")
+		}
+		last += int(delta)
+		rep.Inputs = append(rep.Inputs, Input{
+			Tick:  last,
+			Snake: int(b >> dirBits),
+			Dir:   sim.Direction(b & dirMask),
+		})
+	}
+
+	return rep, nil
+}
+
+// Load reads the replay file at the given path
+func Load(path string) (*Replay, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	return Read(f)
+}
+
+// Save writes the replay to a file at the given path
+func (r *Replay) Save(path string) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+
+	if err := r.Write(f); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
@@ -0,0 +1,105 @@
+package replay_test
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+
+	"github.com/adan-ea/GoSnakeGo/bot"
+	"github.com/adan-ea/GoSnakeGo/replay"
+	"github.com/adan-ea/GoSnakeGo/sim"
+)
+
+// record plays a game between two bots and returns its replay along with
+// the board it ended on
+func record(t *testing.T, seed uint64) (*replay.Replay, *sim.Board) {
+	t.Helper()
+	cfg := sim.Config{
+		Size:  sim.Medium,
+		Food:  sim.ClassicFood,
+		Power: true,
+		Seed:  seed,
+		Snakes: []sim.SnakeConfig{
+			{Color: sim.Blue, Bot: true},
+			{Color: sim.Red, Bot: true},
+		},
+	}
+	controllers := []sim.Controller{bot.New(bot.Cautious), bot.New(bot.Pathfinding)}
+
+	rep := replay.New(cfg)
+	b := sim.NewBoard(cfg)
+	for !b.GameOver() && b.Ticks() < 20000 {
+		actions, _ := b.TickWith(controllers)
+		rep.Record(b.Ticks(), actions...)
+	}
+	if len(rep.Inputs) == 0 {
+		t.Fatal("the bots never turned")
+	}
+	return rep, b
+}
+
+func TestWriteRead(t *testing.T) {
+	rep, _ := record(t, 7)
+
+	var buf bytes.Buffer
+	if err := rep.Write(&buf); err != nil {
+		t.Fatal(err)
+	}
+	got, err := replay.Read(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, rep) {
+		t.Errorf("read %+v, want %+v", got, rep)
+	}
+}
+
+func TestPlayerReplaysTheGame(t *testing.T) {
+	for seed := range uint64(5) {
+		rep, b := record(t, seed)
+
+		p := replay.NewPlayer(rep)
+		for !p.Done() {
+			p.Step()
+		}
+
+		want, err := b.Snapshot()
+		if err != nil {
+			t.Fatal(err)
+		}
+		got, err := p.Board().Snapshot()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Fatalf("seed %d: the replay ends on another board than the game", seed)
+		}
+	}
+}
+
+func TestReadDamaged(t *testing.T) {
+	rep, _ := record(t, 7)
+	var buf bytes.Buffer
+	if err := rep.Write(&buf); err != nil {
+		t.Fatal(err)
+	}
+	data := buf.Bytes()
+
+	tests := []struct {
+		name string
+		data []byte
+	}{
+		{"empty", nil},
+		{"not a replay", []byte("PNG\x00 not a replay")},
+		{"truncated", data[:len(data)-1]},
+		// a config length of 2^63 right after the magic and the version
+		{"huge config", append([]byte("GSGR"), sim.RulesVersion, 0x80, 0x80, 0x80, 0x80, 0x80, 0x80, 0x80, 0x80, 0x80, 0x01)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := replay.Read(bytes.NewReader(tt.data)); err == nil {
+				t.Error("read a damaged replay")
+			}
+		})
+	}
+}
@@ -20,6 +20,21 @@ var (
 	//go:embed game_over.wav
 	GameOver_wav []byte
 
+	//go:embed golden.wav
+	Golden_wav []byte
+
+	//go:embed poison.wav
+	Poison_wav []byte
+
+	//go:embed bonus.wav
+	Bonus_wav []byte
+
+	//go:embed big.wav
+	Big_wav []byte
+
+	//go:embed powerup.wav
+	PowerUp_wav []byte
+
 	//go:embed tetris-theme.wav
 	TetrisTheme_wav []byte
 )
@@ -30,6 +45,11 @@ var (
 	EatPlayer      *audio.Player
 	GameOverPlayer *audio.Player
 	ThemePlayer    *audio.Player
+	GoldenPlayer   *audio.Player
+	PoisonPlayer   *audio.Player
+	BonusPlayer    *audio.Player
+	BigPlayer      *audio.Player
+	PowerUpPlayer  *audio.Player
 )
 
 func InitAudio() {
@@ -76,14 +96,41 @@ func InitAudio() {
 	if err != nil {
 		log.Fatal(err)
 	}
+
+	GoldenPlayer = newWavPlayer(Golden_wav)
+	PoisonPlayer = newWavPlayer(Poison_wav)
+	BonusPlayer = newWavPlayer(Bonus_wav)
+	BigPlayer = newWavPlayer(Big_wav)
+	PowerUpPlayer = newWavPlayer(PowerUp_wav)
+}
+
+func newWavPlayer(data []byte) *audio.Player {
+	d, err := wav.DecodeWithoutResampling(bytes.NewReader(data))
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	p, err := AudioContext.NewPlayer(d)
+	if err != nil {
+		log.Fatal(err)
+	}
+	return p
 }
 
+// Volumes between 0 and 1 of the music and of the sound effects
+var (
+	MusicVolume = 1.0
+	SoundVolume = 1.0
+)
+
 func PlayOnce(p *audio.Player) {
+	p.SetVolume(SoundVolume)
 	p.Rewind()
 	p.Play()
 }
 
 func PlayLoop(p *audio.Player) {
+	p.SetVolume(MusicVolume)
 	if !p.IsPlaying() {
 		p.Rewind()
 		p.Play()
@@ -26,7 +26,19 @@ const (
 
 // Food Sprites
 const (
-	foodSpritePath = "resources/images/food/apple.png"
+	foodSpritePath        = "resources/images/food/apple.png"
+	goldenAppleSpritePath = "resources/images/food/golden_apple.png"
+	poisonAppleSpritePath = "resources/images/food/poison_apple.png"
+	bonusAppleSpritePath  = "resources/images/food/bonus_apple.png"
+	bigAppleSpritePath    = "resources/images/food/big_apple.png"
+)
+
+// Power-up Sprites
+const (
+	slowMotionSpritePath = "resources/images/powerups/slow_motion.png"
+	ghostSpritePath      = "resources/images/powerups/ghost.png"
+	magnetSpritePath     = "resources/images/powerups/magnet.png"
+	shieldSpritePath     = "resources/images/powerups/shield.png"
 )
 
 // UI Sprites
@@ -50,6 +62,8 @@ var (
 	BodySprite       map[int]*ebiten.Image
 	TailSprite       map[int]*ebiten.Image
 	FoodSprite       *ebiten.Image
+	FoodKindSprite   map[int]*ebiten.Image
+	PowerUpSprite    map[int]*ebiten.Image
 	NumbersSprite    *ebiten.Image
 	TrophySprite     *ebiten.Image
 	IconSprite       *ebiten.Image
@@ -82,6 +96,19 @@ func InitImages() {
 	}
 
 	FoodSprite = loadImage(foodSpritePath)
+	FoodKindSprite = map[int]*ebiten.Image{
+		0: FoodSprite,
+		1: loadImage(goldenAppleSpritePath),
+		2: loadImage(poisonAppleSpritePath),
+		3: loadImage(bonusAppleSpritePath),
+		4: loadImage(bigAppleSpritePath),
+	}
+	PowerUpSprite = map[int]*ebiten.Image{
+		0: loadImage(slowMotionSpritePath),
+		1: loadImage(ghostSpritePath),
+		2: loadImage(magnetSpritePath),
+		3: loadImage(shieldSpritePath),
+	}
 	NumbersSprite = loadImage(numbersSpritePath)
 	TrophySprite = loadImage(trophySpritePath)
 	IconSprite = loadImage(iconSpritePath)
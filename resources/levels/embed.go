@@ -0,0 +1,47 @@
+package levels
+
+import (
+	"bytes"
+	_ "embed"
+	"log"
+
+	"github.com/adan-ea/GoSnakeGo/sim"
+)
+
+var (
+	//go:embed pillars.txt
+	Pillars_txt []byte
+
+	//go:embed cross.txt
+	Cross_txt []byte
+
+	//go:embed garden.txt
+	Garden_txt []byte
+
+	//go:embed tunnels.txt
+	Tunnels_txt []byte
+)
+
+// Built-in levels in the order they are offered
+var Levels []*sim.Level
+
+func InitLevels() {
+	Levels = nil
+	for _, data := range [][]byte{Pillars_txt, Cross_txt, Garden_txt, Tunnels_txt} {
+		level, err := sim.ParseLevel(bytes.NewReader(data))
+		if err != nil {
+			log.Fatal(err)
+		}
+		Levels = append(Levels, level)
+	}
+}
+
+// ByName returns the built-in level with the given name, nil if there is none
+func ByName(name string) *sim.Level {
+	for _, level := range Levels {
+		if level.Name == name {
+			return level
+		}
+	}
+	return nil
+}
@@ -0,0 +1,187 @@
+// Package scoreboard keeps the best scores of each kind of board, shared by
+// every frontend
+package scoreboard
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/adan-ea/GoSnakeGo/sim"
+	"github.com/adan-ea/GoSnakeGo/storage"
+)
+
+const (
+	fileName = "scoreboard.txt"
+	nbSaved  = 5 // scores kept for each table
+)
+
+// scoresPath returns the path of the scoreboard file in the config directory
+func scoresPath() (string, error) {
+	dir, err := storage.Dir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, fileName), nil
+}
+
+// Table returns the name of the table the board's scores go to, empty for
+// boards where two players play against each other
+func Table(b *sim.Board) string {
+	players, bots := 0, 0
+	for _, s := range b.Snakes() {
+		if s.Bot() {
+			bots++
+		} else {
+			players++
+		}
+	}
+	if players == 2 {
+		return ""
+	}
+
+	size := sim.SizeFromRowsCols(b.Rows(), b.Cols())
+	table := size.String()
+	if size == sim.CustomSize {
+		// custom boards are only compared with boards of the same dimensions
+		table = fmt.Sprintf("%dx%d", b.Cols(), b.Rows())
+	}
+	if level := b.Level(); level != nil {
+		// semicolons separate the fields of the scoreboard file
+		table = "Level " + strings.ReplaceAll(level.Name, ";", ",")
+	}
+	if b.Walls() == sim.WrapWalls {
+		table += " Wrap"
+	}
+	if bots > 0 {
+		table += " Rivals"
+	}
+	if t := b.TimeAttack(); t != nil {
+		table += fmt.Sprintf(" Time %ds", t.Budget/sim.TicksPerSecond)
+	}
+	return table
+}
+
+// Entry is a score kept on the scoreboard
+type Entry struct {
+	Time  string // when the score was made, as 2006-01-02 15:04:05
+	Table string
+	Score int
+}
+
+// Save saves the score along with the current date, table name, and time to
+// the scoreboard file, creating the file if there is none
+func Save(score int, table string) error {
+	if score == 0 {
+		return nil
+	}
+
+	path, err := scoresPath()
+	if err != nil {
+		return err
+	}
+
+	// Read the file contents
+	f, err := os.ReadFile(path)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+
+	// Add the new score with date, size, and time to the file content
+	currentTime := time.Now().Format("2006-01-02 15:04:05")
+	scores, err := parse(string(f) + fmt.Sprintf("%s;%s;%d\n", currentTime, table, score))
+	if err != nil {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+
+	sortScores(scores)
+
+	// Keep only the top 5 scores for each size
+	seen := make(map[string]int)
+	var topScores []string
+	for _, s := range scores {
+		key := s.Table
+		if seen[key] < nbSaved {
+			topScores = append(topScores, fmt.Sprintf("%s;%s;%d", s.Time, s.Table, s.Score))
+			seen[key]++
+		}
+	}
+
+	// Join the top scores into a single string with newlines
+	fileContent := strings.Join(topScores, "\n") + "\n"
+
+	// Write the top scores back to the file
+	return os.WriteFile(path, []byte(fileContent), 0644)
+}
+
+// Best returns the highest score from the scoreboard file for the specified table
+func Best(table string) int {
+	scores, err := Entries()
+	if err != nil {
+		return 0
+	}
+
+	var highestScore int
+	for _, s := range scores {
+		if s.Table == table && s.Score > highestScore {
+			highestScore = s.Score
+		}
+	}
+	return highestScore
+}
+
+// Entries returns the scores kept on the scoreboard, sorted by table and
+// from the best score to the worst. There are none before the first score
+// is saved.
+func Entries() ([]Entry, error) {
+	path, err := scoresPath()
+	if err != nil {
+		return nil, err
+	}
+	f, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil // no score was made yet
+	}
+	if err != nil {
+		return nil, err
+	}
+	scores, err := parse(string(f))
+	if err != nil {
+		return nil, err
+	}
+	sortScores(scores)
+	return scores, nil
+}
+
+// sortScores sorts the scores by table and then by score in descending order
+func sortScores(scores []Entry) {
+	sort.SliceStable(scores, func(i, j int) bool {
+		if scores[i].Table != scores[j].Table {
+			return scores[i].Table < scores[j].Table
+		}
+		return scores[i].Score > scores[j].Score
+	})
+}
+
+// parse returns the scores of the scoreboard file's content, one
+// time;table;score line per score
+func parse(content string) ([]Entry, error) {
+	var scores []Entry
+	for _, line := range strings.Split(strings.TrimSpace(content), "\n") {
+		parts := strings.Split(line, ";")
+		if len(parts) != 3 {
+			continue // Skip invalid entries
+		}
+		score, err := strconv.Atoi(parts[2])
+		if err != nil {
+			return nil, err
+		}
+		scores = append(scores, Entry{Time: parts[0], Table: parts[1], Score: score})
+	}
+	return scores, nil
+}
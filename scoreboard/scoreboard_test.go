@@ -0,0 +1,86 @@
+package scoreboard_test
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/adan-ea/GoSnakeGo/scoreboard"
+	"github.com/adan-ea/GoSnakeGo/sim"
+)
+
+func TestTable(t *testing.T) {
+	tests := []struct {
+		name  string
+		cfg   sim.Config
+		table string
+	}{
+		{"size", sim.Config{Size: sim.ExtraLarge}, "Extra Large"},
+		{"custom size", sim.Config{Size: sim.CustomSize, Rows: 12, Cols: 30}, "30x12"},
+		{"wrap", sim.Config{Size: sim.Small, Walls: sim.WrapWalls}, "Small Wrap"},
+		{"time attack", sim.Config{Size: sim.Small, TimeAttack: &sim.TimeAttack{Budget: 60 * sim.TicksPerSecond}}, "Small Time 60s"},
+		{
+			"wrap time attack",
+			sim.Config{Size: sim.Medium, Walls: sim.WrapWalls, TimeAttack: &sim.TimeAttack{Budget: 120 * sim.TicksPerSecond}},
+			"Medium Wrap Time 120s",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if table := scoreboard.Table(sim.NewBoard(tt.cfg)); table != tt.table {
+				t.Errorf("table %q, want %q", table, tt.table)
+			}
+		})
+	}
+}
+
+// useTempConfig points the user config directory, where the scoreboard is
+// kept, to a directory of the test
+func useTempConfig(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", dir)
+	t.Setenv("HOME", dir)
+	t.Setenv("AppData", dir)
+}
+
+func TestNoScores(t *testing.T) {
+	useTempConfig(t)
+	scores, err := scoreboard.Entries()
+	if err != nil || len(scores) != 0 {
+		t.Errorf("scores %v, %v before saving any", scores, err)
+	}
+	if best := scoreboard.Best("Small"); best != 0 {
+		t.Errorf("best score %d before saving any", best)
+	}
+}
+
+func TestSaveKeepsTheBestScores(t *testing.T) {
+	useTempConfig(t)
+	const timed = "Small Time 60s"
+	for _, score := range []int{3, 9, 0, 1, 7, 12, 5, 8} {
+		if err := scoreboard.Save(score, timed); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := scoreboard.Save(4, "Small"); err != nil {
+		t.Fatal(err)
+	}
+
+	scores, err := scoreboard.Entries()
+	if err != nil {
+		t.Fatal(err)
+	}
+	kept := map[string][]int{}
+	for _, s := range scores {
+		kept[s.Table] = append(kept[s.Table], s.Score)
+	}
+	if want := []int{12, 9, 8, 7, 5}; !slices.Equal(kept[timed], want) {
+		t.Errorf("kept %v on %q, want %v", kept[timed], timed, want)
+	}
+	if want := []int{4}; !slices.Equal(kept["Small"], want) {
+		t.Errorf("kept %v on Small, want %v", kept["Small"], want)
+	}
+	if best := scoreboard.Best(timed); best != 12 {
+		t.Errorf("best score %d, want 12", best)
+	}
+}
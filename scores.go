@@ -0,0 +1,42 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"strings"
+
+	"github.com/adan-ea/GoSnakeGo/scoreboard"
+)
+
+// runScores prints the high scores of every table, or of the table given
+// with -table
+func runScores(args []string) error {
+	fs := flag.NewFlagSet("scores", flag.ExitOnError)
+	table := fs.String("table", "", `only print the scores of this table, like "Small Wrap"`)
+	fs.Parse(args)
+
+	scores, err := scoreboard.Entries()
+	if err != nil {
+		return err
+	}
+
+	last, rank := "", 0
+	for _, s := range scores {
+		if *table != "" && !strings.EqualFold(s.Table, *table) {
+			continue
+		}
+		if s.Table != last {
+			if last != "" {
+				fmt.Println()
+			}
+			fmt.Println(s.Table)
+			last, rank = s.Table, 0
+		}
+		rank++
+		fmt.Printf("  %d. %4d  %s\n", rank, s.Score, s.Time)
+	}
+	if last == "" {
+		fmt.Println("no scores yet")
+	}
+	return nil
+}
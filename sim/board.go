@@ -0,0 +1,696 @@
+// Package sim implements the rules of the game without any dependency on
+// Ebiten, so games can be simulated headless.
+package sim
+
+import (
+	"math/rand/v2"
+	"slices"
+)
+
+// RulesVersion changes whenever the rules change in a way that makes the same
+// inputs play out differently
+const RulesVersion = 6
+
+// MaxSnakes is the most snakes a board can start with, each one at a mirror
+// image of the first one's start
+const MaxSnakes = 4
+
+// Action is the input given to the snake for one tick
+type Action struct {
+	Turn bool      // whether the snake should queue a change of direction
+	Dir  Direction // the requested direction when Turn is set
+}
+
+// EventKind identifies what happened during a step
+type EventKind int
+
+const (
+	EventAte         EventKind = iota // the snake ate the food
+	EventDied                         // the snake left the board or hit a snake
+	EventWon                          // the goal of the board was reached or the board is full
+	EventFoodExpired                  // the food disappeared before being eaten
+	EventPowerUp                      // the snake picked up a power-up
+	EventShieldBroke                  // the snake hit a wall while shielded and survived
+	EventTimeUp                       // the time of a time attack ran out
+)
+
+// Event is something that happened during a step
+type Event struct {
+	Kind  EventKind
+	Snake int        // index of the snake it happened to
+	Pos   Point      // where the snake's head was when it happened, or where the food was when it expired
+	Food  FoodKind   // kind of the food eaten or expired
+	Power PowerKind  // kind of the power-up picked up
+	Cause DeathCause // what killed the snake
+}
+
+// DeathCause tells what killed a snake
+type DeathCause int
+
+const (
+	NoCause     DeathCause = iota
+	CauseWall              // the snake left the board or hit a wall tile
+	CauseSelf              // the snake hit its own body
+	CauseSnake             // the snake hit the body of another snake
+	CauseHeadOn            // the snake met the head of a snake at least as long
+)
+
+// String returns the name of the cause, empty for NoCause
+func (c DeathCause) String() string {
+	switch c {
+	case CauseWall:
+		return "wall"
+	case CauseSelf:
+		return "self"
+	case CauseSnake:
+		return "snake"
+	case CauseHeadOn:
+		return "head-on"
+	}
+	return ""
+}
+
+// Config holds the options a board is created with
+type Config struct {
+	Size  Size      `json:"size"`
+	Rows  int       `json:"rows,omitempty"` // rows of a CustomSize board
+	Cols  int       `json:"cols,omitempty"` // columns of a CustomSize board
+	Color Color     `json:"color"`
+	Walls Walls     `json:"walls"`
+	Level *Level    `json:"level,omitempty"` // layout of the board, Size is ignored when set
+	Goal  *Goal     `json:"goal,omitempty"`  // goal that wins the game, nil to play until the snake dies
+	Food  FoodTable `json:"food,omitempty"`  // chance of each kind of food to spawn, only apples when empty
+	Power bool      `json:"power,omitempty"` // whether power-ups spawn on the board
+	// time limit of the game, nil to play without one
+	TimeAttack *TimeAttack `json:"timeAttack,omitempty"`
+	Speed      SpeedCurve  `json:"speed"` // DefaultSpeed when left empty
+	Seed       uint64      `json:"seed"`  // seed of the random number generator, see NewSeed
+	// snakes on the board, one player snake of the given Color when empty
+	Snakes []SnakeConfig `json:"snakes,omitempty"`
+}
+
+// SnakeConfig holds the options of one of the snakes on a board
+type SnakeConfig struct {
+	Color Color `json:"color"`
+	Bot   bool  `json:"bot,omitempty"` // the snake is not controlled by a player
+}
+
+// NewSeed returns a new random seed for a game
+func NewSeed() uint64 {
+	return rand.Uint64()
+}
+
+// Board holds the whole state of a game
+type Board struct {
+	rows      int
+	cols      int
+	walls     Walls
+	level     *Level
+	obstacles map[Point]bool
+	goal      *Goal
+	foodTable FoodTable
+	food      *Food
+	powerUps  bool // whether power-ups spawn
+	powerUp   *PowerUp
+	// tick on which the next power-up spawns
+	nextPowerUp int
+	timeAttack  *TimeAttack
+	deadline    int // tick on which the time runs out
+	timeUp      bool
+	// snakes on the board, the first one is the player's in a solo game
+	snakes    []*Snake
+	gameOver  bool
+	won       bool
+	ticks     int
+	scheduler *Scheduler
+	seed      uint64
+	pcg       *rand.PCG // source of rng, kept to save its state
+	rng       *rand.Rand
+}
+
+// NewBoard creates a board from the given config
+func NewBoard(cfg Config) *Board {
+	if cfg.Speed == (SpeedCurve{}) {
+		cfg.Speed = DefaultSpeed
+	}
+	if len(cfg.Snakes) == 0 {
+		cfg.Snakes = []SnakeConfig{{Color: cfg.Color}}
+	}
+
+	pcg := rand.NewPCG(cfg.Seed, cfg.Seed)
+	rng := rand.New(pcg)
+
+	// Draw the random options even when they are not used so the food
+	// sequence only depends on the seed
+	randomSize := Size(rng.IntN(int(RandomSize)))
+	randomColor := Color(rng.IntN(NbColors - 1))
+	if cfg.Size == RandomSize {
+		cfg.Size = randomSize
+	}
+
+	rows, cols := GridSize(cfg.Size)
+	if cfg.Size == CustomSize {
+		rows, cols = ClampSide(cfg.Rows), ClampSide(cfg.Cols)
+	}
+	head, dir := Point{X: 3, Y: 1}, Right
+	if cfg.Level != nil {
+		rows, cols = cfg.Level.Rows, cfg.Level.Cols
+		head, dir = cfg.Level.Start, cfg.Level.StartDir
+	}
+
+	board := &Board{
+		rows:       rows,
+		cols:       cols,
+		walls:      cfg.Walls,
+		level:      cfg.Level,
+		obstacles:  cfg.Level.obstacleSet(),
+		goal:       cfg.Goal,
+		foodTable:  cfg.Food,
+		timeAttack: cfg.TimeAttack,
+		scheduler:  newScheduler(cfg.Speed),
+		seed:       cfg.Seed,
+		pcg:        pcg,
+		rng:        rng,
+	}
+	for i, sc := range cfg.Snakes {
+		color := sc.Color
+		if color == RandomColor {
+			// snakes with a random color each get a different one
+			color = (randomColor + Color(i)) % (NbColors - 1)
+		}
+		start, startDir := mirrorStart(head, dir, i, rows, cols)
+		snake := newSnake(start, startDir, color)
+		snake.bot = sc.Bot
+		board.snakes = append(board.snakes, snake)
+	}
+
+	board.placeFood()
+	if cfg.TimeAttack != nil {
+		board.deadline = cfg.TimeAttack.Budget
+	}
+	if cfg.Power {
+		board.powerUps = true
+		board.nextPowerUp = board.powerUpDelay()
+	}
+
+	return board
+}
+
+// mirrorStart returns where the i-th snake starts. The first one starts at
+// the given head, the others at its mirror images so that symmetric boards
+// are fair to everyone. The second one is mirrored through the center of the
+// board and the last two through its diagonal first, so four snakes starting
+// along the top edge, like the default one, follow the four edges one behind
+// the other instead of running into each other.
+func mirrorStart(head Point, dir Direction, i, rows, cols int) (Point, Direction) {
+	flipX := func() {
+		head.X = cols - 1 - head.X
+		if dir == Left || dir == Right {
+			dir = dir.Opposite()
+		}
+	}
+	flipY := func() {
+		head.Y = rows - 1 - head.Y
+		if dir == Up || dir == Down {
+			dir = dir.Opposite()
+		}
+	}
+
+	// swaps the rows and the columns, a snake going right along the top edge
+	// goes down along the left edge
+	transpose := func() {
+		head.X, head.Y = head.Y, head.X
+		switch dir {
+		case Right:
+			dir = Down
+		case Down:
+			dir = Right
+		case Left:
+			dir = Up
+		case Up:
+			dir = Left
+		}
+	}
+
+	switch i % MaxSnakes {
+	case 1:
+		flipX()
+		flipY()
+	case 2:
+		transpose()
+		flipX()
+	case 3:
+		transpose()
+		flipY()
+	}
+	return head, dir
+}
+
+// Rows returns the number of rows of the board
+func (b *Board) Rows() int {
+	return b.rows
+}
+
+// Cols returns the number of columns of the board
+func (b *Board) Cols() int {
+	return b.cols
+}
+
+// Walls returns what happens when the snake reaches the edge of the board
+func (b *Board) Walls() Walls {
+	return b.walls
+}
+
+// Level returns the layout of the board, nil for an empty board
+func (b *Board) Level() *Level {
+	return b.level
+}
+
+// InBounds reports whether the position is on the board. The head of a dead
+// snake can be off the board.
+func (b *Board) InBounds(p Point) bool {
+	return p.X >= 0 && p.Y >= 0 && p.X < b.cols && p.Y < b.rows
+}
+
+// Obstacle reports whether there is a wall tile at the given position
+func (b *Board) Obstacle(p Point) bool {
+	return b.obstacles[p]
+}
+
+// Snake returns the first snake on the board, the player's in a solo game
+func (b *Board) Snake() *Snake {
+	return b.snakes[0]
+}
+
+// Snakes returns every snake on the board, including the dead ones.
+// The returned slice must not be modified.
+func (b *Board) Snakes() []*Snake {
+	return b.snakes
+}
+
+// Food returns the food currently on the board
+func (b *Board) Food() *Food {
+	return b.food
+}
+
+// Score returns the number of points scored so far by the first snake
+func (b *Board) Score() int {
+	return b.snakes[0].score
+}
+
+// Eaten returns the number of apples eaten so far by the first snake, not
+// counting poison
+func (b *Board) Eaten() int {
+	return b.snakes[0].eaten
+}
+
+// Goal returns the goal of the board, nil when there is none
+func (b *Board) Goal() *Goal {
+	return b.goal
+}
+
+// GameOver reports whether the game has ended, because the snakes died or
+// because the goal was reached
+func (b *Board) GameOver() bool {
+	return b.gameOver
+}
+
+// Won reports whether the game ended by reaching its goal or by filling the board
+func (b *Board) Won() bool {
+	return b.won
+}
+
+// Winner returns the index of the last snake standing: the only player
+// alive on a board with several players, or the only snake alive on a board
+// of bots. It returns -1 when there is none.
+func (b *Board) Winner() int {
+	players := b.players()
+	if len(b.snakes) < 2 || players == 1 {
+		return -1
+	}
+
+	winner := -1
+	for i, s := range b.snakes {
+		if s.dead || (players > 0 && s.bot) {
+			continue
+		}
+		if winner >= 0 {
+			return -1
+		}
+		winner = i
+	}
+	return winner
+}
+
+// players returns the number of snakes controlled by players
+func (b *Board) players() int {
+	players := 0
+	for _, s := range b.snakes {
+		if !s.bot {
+			players++
+		}
+	}
+	return players
+}
+
+// Seed returns the seed the board was created with
+func (b *Board) Seed() uint64 {
+	return b.seed
+}
+
+// Ticks returns the number of ticks played so far
+func (b *Board) Ticks() int {
+	return b.ticks
+}
+
+// Tick advances the game by one tick, actions[i] being the input of the i-th
+// snake. Turns are queued on the snakes and applied one per move when the
+// scheduler moves them.
+func (b *Board) Tick(actions ...Action) []Event {
+	if b.gameOver {
+		return nil
+	}
+
+	b.ticks++
+	b.queueTurns(actions)
+
+	events := b.expire()
+	if b.scheduler.Tick(b.interval()) {
+		events = append(events, b.moveSnakes()...)
+	}
+	events = append(events, b.checkGoal()...)
+
+	return append(events, b.checkTime()...)
+}
+
+// Step queues the actions and moves the snakes right away, returning what
+// happened. The move counts as the ticks it takes under Tick, so goals,
+// timers and food that disappears see the same game time.
+func (b *Board) Step(actions ...Action) []Event {
+	if b.gameOver {
+		return nil
+	}
+
+	b.queueTurns(actions)
+	b.ticks += b.interval()
+	b.scheduler.wait = 0
+
+	events := append(b.expire(), b.moveSnakes()...)
+	events = append(events, b.checkGoal()...)
+
+	return append(events, b.checkTime()...)
+}
+
+// expire replaces the food whose time is up and spawns or removes power-ups
+func (b *Board) expire() []Event {
+	var events []Event
+	if b.food.expires > 0 && b.ticks >= b.food.expires {
+		events = append(events, Event{Kind: EventFoodExpired, Pos: b.food.Pos(), Food: b.food.kind})
+		if !b.placeFood() {
+			// the food stays until there is room for new food
+			b.food.expires = 0
+		}
+	}
+
+	b.updatePowerUp()
+	return events
+}
+
+// queueTurns gives each snake its action
+func (b *Board) queueTurns(actions []Action) {
+	for i, action := range actions {
+		if i < len(b.snakes) && action.Turn {
+			b.snakes[i].changeDirection(action.Dir)
+		}
+	}
+}
+
+// checkGoal ends the game when its goal is reached by the first snake
+func (b *Board) checkGoal() []Event {
+	if b.gameOver || b.goal == nil || !b.goal.Reached(b) {
+		return nil
+	}
+
+	b.gameOver = true
+	b.won = true
+	return []Event{{Kind: EventWon, Pos: b.snakes[0].Head()}}
+}
+
+// Slow motion multiplies the ticks between two moves by this factor
+const slowMotionFactor = 2
+
+// interval returns the number of ticks until the next move
+func (b *Board) interval() int {
+	// the best score sets the pace when several snakes play
+	score := 0
+	slow := false
+	for _, s := range b.snakes {
+		score = max(score, s.score)
+		slow = slow || b.active(s, SlowMotion)
+	}
+
+	interval := b.scheduler.Interval(score)
+	if slow {
+		interval *= slowMotionFactor
+	}
+	return interval
+}
+
+// moveSnakes moves every snake at once, then resolves collisions, power-ups
+// and food
+func (b *Board) moveSnakes() []Event {
+	var events []Event
+	alive := b.alive()
+
+	for _, s := range alive {
+		// keep the snake as it was in case the shield cancels the move
+		var prevBody []Point
+		prevGrowth := s.growth
+		if b.active(s, Shield) {
+			prevBody = slices.Clone(s.body)
+		}
+
+		// remove tail first, add 1 in front
+		s.move()
+		if b.walls == WrapWalls {
+			s.body[len(s.body)-1] = b.wrap(s.Head())
+		}
+
+		if b.hitsWall(s) && prevBody != nil {
+			// the shield breaks instead of the snake
+			events = append(events, Event{Kind: EventShieldBroke, Snake: b.index(s), Pos: s.Head()})
+			s.body = prevBody
+			s.growth = prevGrowth
+			s.effects[Shield] = 0
+		}
+	}
+
+	// snakes die only once all of them have moved, so two snakes can kill
+	// each other
+	var died []Event
+	for _, s := range alive {
+		if cause := b.collides(s, alive); cause != NoCause {
+			died = append(died, Event{Kind: EventDied, Snake: b.index(s), Pos: s.Head(), Cause: cause})
+		}
+	}
+	for _, e := range died {
+		b.snakes[e.Snake].dead = true
+	}
+	events = append(events, died...)
+	if b.checkOver() {
+		return events
+	}
+
+	alive = b.alive()
+	events = append(events, b.pickUpPowerUp(alive)...)
+	b.pullFood(alive)
+
+	for _, s := range alive {
+		if !s.headHits(b.food.x, b.food.y) {
+			continue
+		}
+
+		kind := b.food.kind
+		eaten := kind.Type()
+		// the snake grows on the next moves, poison shrinks it right away
+		s.grow(eaten.Growth)
+
+		placed := b.placeFood()
+		s.score += eaten.Points
+		// poison does not count toward apple goals
+		if eaten.Points > 0 || eaten.Growth > 0 {
+			s.eaten++
+		}
+		if b.timeAttack != nil {
+			b.deadline += b.timeAttack.Bonus
+		}
+		events = append(events, Event{Kind: EventAte, Snake: b.index(s), Pos: s.Head(), Food: kind})
+
+		// filling the whole board wins the game
+		if !placed {
+			b.gameOver = true
+			b.won = true
+			events = append(events, Event{Kind: EventWon, Snake: b.index(s), Pos: s.Head()})
+			break
+		}
+	}
+
+	return events
+}
+
+// alive returns the snakes still on the board
+func (b *Board) alive() []*Snake {
+	var alive []*Snake
+	for _, s := range b.snakes {
+		if !s.dead {
+			alive = append(alive, s)
+		}
+	}
+	return alive
+}
+
+// index returns the position of the snake in the board's snakes
+func (b *Board) index(s *Snake) int {
+	return slices.Index(b.snakes, s)
+}
+
+// hitsWall reports whether the snake's head left the board or is on a wall tile
+func (b *Board) hitsWall(s *Snake) bool {
+	head := s.Head()
+	return !b.InBounds(head) || b.obstacles[head]
+}
+
+// collides returns what kills the snake after its move, NoCause when it
+// survives. A head meeting another head kills the shorter snake, or both when
+// they are the same length.
+func (b *Board) collides(s *Snake, snakes []*Snake) DeathCause {
+	if b.hitsWall(s) {
+		return CauseWall
+	}
+	if s.headHitsBody() && !b.active(s, Ghost) {
+		return CauseSelf
+	}
+
+	for _, other := range snakes {
+		if other == s {
+			continue
+		}
+		if s.hitsBodyOf(other) {
+			return CauseSnake
+		}
+		if s.Head() == other.Head() && len(s.body) <= len(other.body) {
+			return CauseHeadOn
+		}
+	}
+	return NoCause
+}
+
+// checkOver ends the game when the player dies, when at most one player is
+// left on a board with several players, or when at most one snake is left
+// on a board of bots
+func (b *Board) checkOver() bool {
+	alive, playersAlive := 0, 0
+	for _, s := range b.snakes {
+		if s.dead {
+			continue
+		}
+		alive++
+		if !s.bot {
+			playersAlive++
+		}
+	}
+
+	switch players := b.players(); {
+	case players > 1:
+		b.gameOver = playersAlive <= 1
+	case players == 1:
+		b.gameOver = playersAlive == 0
+	default:
+		b.gameOver = alive == 0 || (len(b.snakes) > 1 && alive == 1)
+	}
+	return b.gameOver
+}
+
+// wrap brings a point that left the board back on the opposite edge
+func (b *Board) wrap(p Point) Point {
+	return Point{
+		X: (p.X + b.cols) % b.cols,
+		Y: (p.Y + b.rows) % b.rows,
+	}
+}
+
+// placeFood puts new food on a free tile, it reports false when the board is full
+func (b *Board) placeFood() bool {
+	// levels can have fixed places for the food to spawn
+	if b.level != nil && len(b.level.FoodSpawns) > 0 {
+		var free []Point
+		for _, p := range b.level.FoodSpawns {
+			if b.freeTile(p) {
+				free = append(free, p)
+			}
+		}
+		if len(free) > 0 {
+			p := free[b.rng.IntN(len(free))]
+			b.food = b.newFood(p)
+			return true
+		}
+	}
+
+	p, ok := b.randomFreeTile()
+	if !ok {
+		// there is no room left, keep the food where it was
+		return false
+	}
+	b.food = b.newFood(p)
+	return true
+}
+
+// randomFreeTile returns a random tile with nothing on it, if there is one
+func (b *Board) randomFreeTile() (Point, bool) {
+	free := false
+	for y := 0; y < b.rows && !free; y++ {
+		for x := 0; x < b.cols && !free; x++ {
+			free = b.freeTile(Point{X: x, Y: y})
+		}
+	}
+	if !free {
+		return Point{}, false
+	}
+
+	for {
+		p := Point{X: b.rng.IntN(b.cols), Y: b.rng.IntN(b.rows)}
+		if b.freeTile(p) {
+			return p, true
+		}
+	}
+}
+
+// newFood creates food of a kind drawn from the food table at the given position
+func (b *Board) newFood(p Point) *Food {
+	kind := b.foodTable.pick(b.rng.IntN)
+
+	expires := 0
+	if lifetime := kind.Type().Lifetime; lifetime > 0 {
+		expires = b.ticks + lifetime
+	}
+	return newFood(p.X, p.Y, kind, expires)
+}
+
+// freeTile reports whether there is no snake, wall, food or power-up on the tile
+func (b *Board) freeTile(p Point) bool {
+	if b.obstacles[p] {
+		return false
+	}
+	if b.food != nil && b.food.Pos() == p {
+		return false
+	}
+	if b.powerUp != nil && b.powerUp.Pos() == p {
+		return false
+	}
+	for _, s := range b.snakes {
+		if !s.dead && s.occupies(p) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,283 @@
+package sim_test
+
+import (
+	"reflect"
+	"slices"
+	"testing"
+
+	"github.com/adan-ea/GoSnakeGo/bot"
+	"github.com/adan-ea/GoSnakeGo/sim"
+)
+
+// botConfig is a board of two bots eating every kind of food and picking up
+// power-ups, so a game goes through most of the rules
+func botConfig(seed uint64) sim.Config {
+	return sim.Config{
+		Size:  sim.Medium,
+		Food:  sim.FoodTable{sim.Apple: 4, sim.GoldenApple: 1, sim.PoisonApple: 1, sim.BonusApple: 1, sim.BigApple: 1},
+		Power: true,
+		Seed:  seed,
+		Snakes: []sim.SnakeConfig{
+			{Color: sim.Blue, Bot: true},
+			{Color: sim.RandomColor, Bot: true},
+		},
+	}
+}
+
+func botControllers() []sim.Controller {
+	return []sim.Controller{bot.New(bot.Cautious), bot.New(bot.Pathfinding)}
+}
+
+// play ticks the board until the game is over or maxTicks passed, returning
+// every event
+func play(b *sim.Board, controllers []sim.Controller, maxTicks int) []sim.Event {
+	var events []sim.Event
+	for range maxTicks {
+		if b.GameOver() {
+			break
+		}
+		_, ev := b.TickWith(controllers)
+		events = append(events, ev...)
+	}
+	return events
+}
+
+func TestSameSeedSameGame(t *testing.T) {
+	for seed := range uint64(20) {
+		a, b := sim.NewBoard(botConfig(seed)), sim.NewBoard(botConfig(seed))
+		eventsA := play(a, botControllers(), 20000)
+		eventsB := play(b, botControllers(), 20000)
+
+		if !reflect.DeepEqual(eventsA, eventsB) {
+			t.Fatalf("seed %d: the events of two games differ", seed)
+		}
+		stateA, err := a.Snapshot()
+		if err != nil {
+			t.Fatal(err)
+		}
+		stateB, err := b.Snapshot()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(stateA, stateB) {
+			t.Fatalf("seed %d: two games end on different boards", seed)
+		}
+	}
+}
+
+func TestSeedPlacesFood(t *testing.T) {
+	foods := map[sim.Point]bool{}
+	for seed := range uint64(20) {
+		foods[sim.NewBoard(sim.Config{Seed: seed}).Food().Pos()] = true
+	}
+	if len(foods) < 2 {
+		t.Errorf("20 seeds placed the first food on %d tiles", len(foods))
+	}
+}
+
+// boardWith returns a small board holding the given snakes, with the food
+// in a corner out of their way
+func boardWith(t *testing.T, snakes ...sim.SnakeState) *sim.Board {
+	t.Helper()
+	st, err := sim.NewBoard(sim.Config{Size: sim.Small, Seed: 1}).Snapshot()
+	if err != nil {
+		t.Fatal(err)
+	}
+	st.Snakes = snakes
+	st.Food = sim.FoodState{Pos: sim.Point{X: 0, Y: st.Rows - 1}}
+
+	b, err := sim.RestoreBoard(st)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return b
+}
+
+// line returns the body of a snake from tail to head
+func line(points ...sim.Point) []sim.Point {
+	return points
+}
+
+func pt(x, y int) sim.Point {
+	return sim.Point{X: x, Y: y}
+}
+
+func TestCollisions(t *testing.T) {
+	tests := []struct {
+		name    string
+		snakes  []sim.SnakeState
+		actions []sim.Action
+		died    map[int]sim.DeathCause // cause of death of each snake that dies
+	}{
+		{
+			name:   "wall",
+			snakes: []sim.SnakeState{{Body: line(pt(11, 1), pt(12, 1), pt(13, 1)), Direction: sim.Right}},
+			died:   map[int]sim.DeathCause{0: sim.CauseWall},
+		},
+		{
+			name:   "top wall",
+			snakes: []sim.SnakeState{{Body: line(pt(3, 2), pt(3, 1), pt(3, 0)), Direction: sim.Up}},
+			died:   map[int]sim.DeathCause{0: sim.CauseWall},
+		},
+		{
+			name:    "own body",
+			snakes:  []sim.SnakeState{{Body: line(pt(1, 2), pt(2, 2), pt(3, 2), pt(3, 3), pt(2, 3)), Direction: sim.Left}},
+			actions: []sim.Action{{Turn: true, Dir: sim.Up}},
+			died:    map[int]sim.DeathCause{0: sim.CauseSelf},
+		},
+		{
+			name:   "own tail moving away",
+			snakes: []sim.SnakeState{{Body: line(pt(2, 2), pt(3, 2), pt(3, 3), pt(2, 3)), Direction: sim.Up}},
+			died:   map[int]sim.DeathCause{},
+		},
+		{
+			name: "body of another snake",
+			snakes: []sim.SnakeState{
+				{Body: line(pt(1, 5), pt(2, 5), pt(3, 5)), Direction: sim.Right},
+				{Body: line(pt(4, 3), pt(4, 4), pt(4, 5), pt(4, 6)), Direction: sim.Down},
+			},
+			died: map[int]sim.DeathCause{0: sim.CauseSnake},
+		},
+		{
+			name: "head-on of the same length",
+			snakes: []sim.SnakeState{
+				{Body: line(pt(1, 5), pt(2, 5), pt(3, 5)), Direction: sim.Right},
+				{Body: line(pt(7, 5), pt(6, 5), pt(5, 5)), Direction: sim.Left},
+			},
+			died: map[int]sim.DeathCause{0: sim.CauseHeadOn, 1: sim.CauseHeadOn},
+		},
+		{
+			name: "head-on with a longer snake",
+			snakes: []sim.SnakeState{
+				{Body: line(pt(1, 5), pt(2, 5), pt(3, 5)), Direction: sim.Right},
+				{Body: line(pt(8, 5), pt(7, 5), pt(6, 5), pt(5, 5)), Direction: sim.Left},
+			},
+			died: map[int]sim.DeathCause{0: sim.CauseHeadOn},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := boardWith(t, tt.snakes...)
+
+			died := map[int]sim.DeathCause{}
+			for _, e := range b.Step(tt.actions...) {
+				if e.Kind == sim.EventDied {
+					died[e.Snake] = e.Cause
+				}
+			}
+			if !reflect.DeepEqual(died, tt.died) {
+				t.Errorf("died %v, want %v", died, tt.died)
+			}
+			for i, s := range b.Snakes() {
+				_, dead := tt.died[i]
+				if s.Alive() == dead {
+					t.Errorf("snake %d alive = %v, want %v", i, s.Alive(), !dead)
+				}
+			}
+		})
+	}
+}
+
+func TestWrapWalls(t *testing.T) {
+	b := boardWith(t, sim.SnakeState{Body: line(pt(11, 1), pt(12, 1), pt(13, 1)), Direction: sim.Right})
+	st, err := b.Snapshot()
+	if err != nil {
+		t.Fatal(err)
+	}
+	st.Walls = sim.WrapWalls
+	if b, err = sim.RestoreBoard(st); err != nil {
+		t.Fatal(err)
+	}
+
+	b.Step()
+	if s := b.Snake(); !s.Alive() || s.Head() != pt(0, 1) {
+		t.Errorf("head at %v alive %v, want %v alive", s.Head(), s.Alive(), pt(0, 1))
+	}
+}
+
+func TestTurnQueue(t *testing.T) {
+	tests := []struct {
+		name  string
+		turns []sim.Direction
+		queue []sim.Direction
+	}{
+		{"reverse", []sim.Direction{sim.Left}, nil},
+		{"same direction", []sim.Direction{sim.Right}, nil},
+		{"up then left", []sim.Direction{sim.Up, sim.Left}, []sim.Direction{sim.Up, sim.Left}},
+		{"reverse of a queued turn", []sim.Direction{sim.Up, sim.Down}, []sim.Direction{sim.Up}},
+		{"queued twice", []sim.Direction{sim.Up, sim.Up}, []sim.Direction{sim.Up}},
+		{"full queue", []sim.Direction{sim.Up, sim.Left, sim.Down, sim.Right}, []sim.Direction{sim.Up, sim.Left, sim.Down}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// slow enough that the snake does not move during the turns
+			b := sim.NewBoard(sim.Config{Speed: sim.SpeedCurve{Base: 100, Min: 100}, Seed: 1})
+			for _, dir := range tt.turns {
+				b.Tick(sim.Action{Turn: true, Dir: dir})
+			}
+
+			st, err := b.Snapshot()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if queue := st.Snakes[0].Queue; !slices.Equal(queue, tt.queue) {
+				t.Errorf("queue %v, want %v", queue, tt.queue)
+			}
+		})
+	}
+}
+
+func TestTurnsApplyOnePerMove(t *testing.T) {
+	b := sim.NewBoard(sim.Config{Size: sim.Medium, Speed: sim.SpeedCurve{Base: 100, Min: 100}, Seed: 1})
+	b.Tick(sim.Action{Turn: true, Dir: sim.Down})
+	b.Tick(sim.Action{Turn: true, Dir: sim.Left})
+
+	s := b.Snake()
+	head := s.Head()
+	for _, dir := range []sim.Direction{sim.Down, sim.Left, sim.Left} {
+		b.Step()
+		head = head.Add(dir, 1)
+		if s.Direction() != dir || s.Head() != head {
+			t.Fatalf("moved %v to %v, want %v to %v", s.Direction(), s.Head(), dir, head)
+		}
+	}
+	if s.Turning() {
+		t.Error("turns left in the queue after every move")
+	}
+}
+
+func TestSnakesStartApart(t *testing.T) {
+	sizes := []sim.Config{
+		{Size: sim.Small},
+		{Size: sim.Medium},
+		{Size: sim.Large},
+		{Size: sim.ExtraLarge},
+		{Size: sim.CustomSize, Rows: sim.MinSide, Cols: sim.MinSide},
+		{Size: sim.CustomSize, Rows: sim.MinSide, Cols: sim.MaxSide},
+		{Size: sim.CustomSize, Rows: sim.MaxSide, Cols: sim.MinSide},
+	}
+
+	for _, cfg := range sizes {
+		for n := 1; n <= sim.MaxSnakes; n++ {
+			for seed := range uint64(10) {
+				cfg.Seed = seed
+				cfg.Snakes = make([]sim.SnakeConfig, n)
+				b := sim.NewBoard(cfg)
+
+				// going straight, every snake has until its head reaches the
+				// wall ahead, 3 tiles from the one behind its tail
+				moves := min(b.Rows(), b.Cols()) - 4
+				for move := range moves {
+					for _, e := range b.Step() {
+						if e.Kind == sim.EventDied {
+							t.Fatalf("%dx%d board with %d snakes, seed %d: snake %d died of %s on move %d",
+								b.Cols(), b.Rows(), n, seed, e.Snake, e.Cause, move+1)
+						}
+					}
+				}
+			}
+		}
+	}
+}
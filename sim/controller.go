@@ -0,0 +1,68 @@
+package sim
+
+// View is a read-only view of a board, given to controllers to pick their moves
+type View interface {
+	Rows() int
+	Cols() int
+	Walls() Walls
+	Obstacle(p Point) bool
+	Snakes() []*Snake
+	Food() *Food
+	PowerUp() *PowerUp
+	Effect(s *Snake, kind PowerKind) int
+	Goal() *Goal
+	TimeLeft() int
+	Ticks() int
+}
+
+// Controller picks the moves of a snake
+type Controller interface {
+	// Action returns the input of the snake at the given index for the next tick
+	Action(v View, snake int) Action
+}
+
+// TickWith asks controllers[i] for the action of the i-th snake, then
+// advances the game by one tick. Snakes without a controller get no input.
+// It returns the actions given so they can be recorded.
+func (b *Board) TickWith(controllers []Controller) ([]Action, []Event) {
+	actions := make([]Action, len(b.snakes))
+	for i, c := range controllers {
+		if c != nil && i < len(actions) {
+			actions[i] = c.Action(b, i)
+		}
+	}
+
+	return actions, b.Tick(actions...)
+}
+
+// Turn is a direction given to a snake on a given tick
+type Turn struct {
+	Tick int // tick on which the direction is given, starting at 1
+	Dir  Direction
+}
+
+// Script is a controller that gives a fixed list of turns
+type Script struct {
+	turns []Turn
+	next  int // index of the next turn to give
+}
+
+// NewScript creates a controller giving the turns in order of their ticks
+func NewScript(turns []Turn) *Script {
+	return &Script{turns: turns}
+}
+
+// Action returns the turn scripted for the next tick, if any
+func (s *Script) Action(v View, snake int) Action {
+	tick := v.Ticks() + 1
+	for s.next < len(s.turns) && s.turns[s.next].Tick < tick {
+		s.next++
+	}
+
+	if s.next < len(s.turns) && s.turns[s.next].Tick == tick {
+		turn := s.turns[s.next]
+		s.next++
+		return Action{Turn: true, Dir: turn.Dir}
+	}
+	return Action{}
+}
@@ -0,0 +1,104 @@
+package sim
+
+// FoodKind identifies the kind of a food
+type FoodKind int
+
+// Number of food kinds available
+const NbFoodKinds = 5
+const (
+	Apple       FoodKind = iota // one point, grows the snake by one
+	GoldenApple                 // worth more points
+	PoisonApple                 // shrinks the snake
+	BonusApple                  // worth more points but disappears after a while
+	BigApple                    // grows the snake by several segments
+)
+
+// FoodType describes the effects of a kind of food
+type FoodType struct {
+	Points   int // points scored when eating it
+	Growth   int // segments the snake grows by, negative to shrink it
+	Lifetime int // ticks before it disappears, 0 to stay until eaten
+}
+
+var foodTypes = map[FoodKind]FoodType{
+	Apple:       {Points: 1, Growth: 1},
+	GoldenApple: {Points: 5, Growth: 1},
+	PoisonApple: {Points: 0, Growth: -2},
+	BonusApple:  {Points: 3, Growth: 1, Lifetime: 5 * TicksPerSecond},
+	BigApple:    {Points: 1, Growth: 4},
+}
+
+// Type returns the effects of the food kind
+func (k FoodKind) Type() FoodType {
+	return foodTypes[k]
+}
+
+// FoodTable gives the relative chance of each kind of food to spawn.
+// A board with an empty table only spawns apples.
+type FoodTable map[FoodKind]int
+
+// Chance of each kind of food to spawn in each mode
+var (
+	ClassicFood = FoodTable{Apple: 80, GoldenApple: 8, BonusApple: 6, BigApple: 4, PoisonApple: 2}
+	WrapFood    = FoodTable{Apple: 70, GoldenApple: 8, BonusApple: 8, BigApple: 6, PoisonApple: 8}
+)
+
+// FoodFor returns the food spawned on boards with the given walls
+func FoodFor(walls Walls) FoodTable {
+	if walls == WrapWalls {
+		return WrapFood
+	}
+	return ClassicFood
+}
+
+// pick returns a kind of food drawn from the table
+func (t FoodTable) pick(draw func(n int) int) FoodKind {
+	total := 0
+	for kind := FoodKind(0); kind < NbFoodKinds; kind++ {
+		total += max(t[kind], 0)
+	}
+	if total == 0 {
+		return Apple
+	}
+
+	// go through the kinds in order so the same draw always gives the same kind
+	n := draw(total)
+	for kind := FoodKind(0); kind < NbFoodKinds; kind++ {
+		n -= max(t[kind], 0)
+		if n < 0 {
+			return kind
+		}
+	}
+	return Apple
+}
+
+// Food represents the food
+type Food struct {
+	x, y    int
+	kind    FoodKind
+	expires int // tick on which the food disappears, 0 if it stays
+}
+
+func newFood(x, y int, kind FoodKind, expires int) *Food {
+	return &Food{
+		x:       x,
+		y:       y,
+		kind:    kind,
+		expires: expires,
+	}
+}
+
+// Pos returns the position of the food on the board
+func (f *Food) Pos() Point {
+	return Point{X: f.x, Y: f.y}
+}
+
+// Kind returns the kind of the food
+func (f *Food) Kind() FoodKind {
+	return f.kind
+}
+
+// Expires returns the tick on which the food disappears, 0 if it stays until eaten
+func (f *Food) Expires() int {
+	return f.expires
+}
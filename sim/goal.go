@@ -0,0 +1,34 @@
+package sim
+
+// GoalKind is what has to be done to win a board
+type GoalKind int
+
+const (
+	GoalApples  GoalKind = iota // eat Target apples
+	GoalLength                  // grow the snake to Target segments
+	GoalSurvive                 // stay alive for Target seconds
+)
+
+// Goal ends the game with a win once it is reached
+type Goal struct {
+	Kind   GoalKind `json:"kind"`
+	Target int      `json:"target"`
+}
+
+// Progress returns how far the board is toward the goal, in the unit of Target
+func (g Goal) Progress(b *Board) int {
+	switch g.Kind {
+	case GoalApples:
+		return b.snakes[0].eaten
+	case GoalLength:
+		return len(b.snakes[0].body)
+	case GoalSurvive:
+		return b.ticks / TicksPerSecond
+	}
+	return 0
+}
+
+// Reached reports whether the board has reached the goal
+func (g Goal) Reached(b *Board) bool {
+	return g.Progress(b) >= g.Target
+}
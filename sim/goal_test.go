@@ -0,0 +1,37 @@
+package sim_test
+
+import (
+	"testing"
+
+	"github.com/adan-ea/GoSnakeGo/sim"
+)
+
+func TestSurviveGoal(t *testing.T) {
+	cfg := sim.Config{Size: sim.Large, Goal: &sim.Goal{Kind: sim.GoalSurvive, Target: 1}, Seed: 1}
+	interval := sim.DefaultSpeed.Interval(0)
+
+	tests := []struct {
+		name  string
+		step  func(b *sim.Board)
+		steps int // steps taken for one second to pass
+	}{
+		{"tick", func(b *sim.Board) { b.Tick() }, sim.TicksPerSecond},
+		{"step", func(b *sim.Board) { b.Step() }, sim.TicksPerSecond / interval},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := sim.NewBoard(cfg)
+			for range tt.steps - 1 {
+				tt.step(b)
+			}
+			if b.GameOver() {
+				t.Fatalf("game over after %d steps", tt.steps-1)
+			}
+
+			tt.step(b)
+			if !b.Won() {
+				t.Errorf("not won after %d steps, %d ticks", tt.steps, b.Ticks())
+			}
+		})
+	}
+}
@@ -0,0 +1,151 @@
+package sim
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Characters of the level format
+const (
+	levelEmpty = '.'
+	levelWall  = '#'
+	levelFood  = '*'
+)
+
+// Direction of the snake for each start character of the level format
+var levelStarts = map[rune]Direction{
+	'^': Up,
+	'v': Down,
+	'<': Left,
+	'>': Right,
+}
+
+// Level is a board layout with obstacles, a start position for the snake
+// and optional fixed places for the food to spawn
+type Level struct {
+	Name       string    `json:"name"`
+	Rows       int       `json:"rows"`
+	Cols       int       `json:"cols"`
+	Obstacles  []Point   `json:"obstacles"`
+	Start      Point     `json:"start"` // position of the snake's head
+	StartDir   Direction `json:"startDir"`
+	FoodSpawns []Point   `json:"foodSpawns,omitempty"`
+}
+
+// ParseLevel reads a level written as a plain text grid, for example
+//
+//	name: Pillars
+//	..........
+//	.>....##..
+//	......##*.
+//	..........
+//
+// where '.' is an empty tile, '#' a wall, '*' a place where food spawns and
+// one of '^', 'v', '<' or '>' the head of the snake facing that direction.
+// The snake's body starts behind its head, the other snakes of versus and
+// rivals start at mirror images of it that must be free too. Lines before the
+// grid are "key: value" headers, only "name" is supported.
+func ParseLevel(r io.Reader) (*Level, error) {
+	level := &Level{}
+	hasStart := false
+
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+
+		if key, value, ok := strings.Cut(line, ":"); ok && level.Rows == 0 {
+			if strings.TrimSpace(key) != "name" {
+				return nil, fmt.Errorf("unknown level header %q", key)
+			}
+			level.Name = strings.TrimSpace(value)
+			continue
+		}
+
+		row := []rune(line)
+		if level.Cols == 0 {
+			level.Cols = len(row)
+		} else if len(row) != level.Cols {
+			return nil, fmt.Errorf("line %d of the grid has %d tiles instead of %d", level.Rows+1, len(row), level.Cols)
+		}
+
+		for x, c := range row {
+			p := Point{X: x, Y: level.Rows}
+			switch c {
+			case levelEmpty:
+			case levelWall:
+				level.Obstacles = append(level.Obstacles, p)
+			case levelFood:
+				level.FoodSpawns = append(level.FoodSpawns, p)
+			default:
+				dir, ok := levelStarts[c]
+				if !ok {
+					return nil, fmt.Errorf("unknown tile %q at %d,%d", c, p.X, p.Y)
+				}
+				if hasStart {
+					return nil, errors.New("level has more than one start")
+				}
+				level.Start = p
+				level.StartDir = dir
+				hasStart = true
+			}
+		}
+		level.Rows++
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	if level.Rows == 0 {
+		return nil, errors.New("level has no grid")
+	}
+	if !hasStart {
+		return nil, errors.New("level has no start")
+	}
+	if err := level.checkStart(); err != nil {
+		return nil, err
+	}
+
+	return level, nil
+}
+
+// checkStart makes sure the starting body of every snake the level can host,
+// up to MaxSnakes in versus with rivals, is on free tiles apart from the others
+func (l *Level) checkStart() error {
+	obstacles := l.obstacleSet()
+	taken := map[Point]bool{}
+	for i := range MaxSnakes {
+		head, dir := mirrorStart(l.Start, l.StartDir, i, l.Rows, l.Cols)
+		for _, p := range startBody(head, dir) {
+			if p.X < 0 || p.Y < 0 || p.X >= l.Cols || p.Y >= l.Rows || obstacles[p] {
+				if i == 0 {
+					return errors.New("the snake does not fit behind its start")
+				}
+				return fmt.Errorf("snake %d does not fit behind its start at %d,%d, mirrored from the first one", i+1, head.X, head.Y)
+			}
+			if taken[p] {
+				return fmt.Errorf("snake %d starts on another snake at %d,%d", i+1, p.X, p.Y)
+			}
+			taken[p] = true
+		}
+	}
+	return nil
+}
+
+// obstacleSet returns the walls of the level as a set, empty for a nil level
+func (l *Level) obstacleSet() map[Point]bool {
+	if l == nil {
+		return map[Point]bool{}
+	}
+
+	set := make(map[Point]bool, len(l.Obstacles))
+	for _, p := range l.Obstacles {
+		set[p] = true
+	}
+	return set
+}
@@ -0,0 +1,53 @@
+package sim_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/adan-ea/GoSnakeGo/resources/levels"
+	"github.com/adan-ea/GoSnakeGo/sim"
+)
+
+func TestParseLevel(t *testing.T) {
+	tests := []struct {
+		name  string
+		level string
+		ok    bool
+	}{
+		{"open", "..........\n...>......\n..........\n..........\n..........\n..........\n..........\n..........\n..........\n..........", true},
+		{"no start", "..........\n..........", false},
+		{"two starts", "..........\n...>..<...\n..........", false},
+		{"ragged rows", "..........\n...>......\n.........", false},
+		{"snake in a wall", "..........\n.#.>......\n..........\n..........", false},
+		// the fourth snake starts going up the left edge, on the wall
+		{"mirrored snake in a wall", "..........\n...>......\n..........\n..........\n..........\n..........\n.#........\n..........\n..........\n..........", false},
+		// the snakes going along the edges would start on each other
+		{"mirrored snakes overlap", ".....\n...>.\n.....\n.....\n.....", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := sim.ParseLevel(strings.NewReader(tt.level))
+			if (err == nil) != tt.ok {
+				t.Errorf("error %v, want ok %v", err, tt.ok)
+			}
+		})
+	}
+}
+
+func TestBuiltInLevelsHostEverySnake(t *testing.T) {
+	for _, data := range [][]byte{levels.Pillars_txt, levels.Cross_txt, levels.Garden_txt, levels.Tunnels_txt} {
+		level, err := sim.ParseLevel(bytes.NewReader(data))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		b := sim.NewBoard(sim.Config{Level: level, Snakes: make([]sim.SnakeConfig, sim.MaxSnakes)})
+		for _, e := range b.Step() {
+			if e.Kind == sim.EventDied {
+				t.Errorf("%s: snake %d died of %s on the first move", level.Name, e.Snake, e.Cause)
+			}
+		}
+	}
+}
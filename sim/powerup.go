@@ -0,0 +1,166 @@
+package sim
+
+// PowerKind identifies the effect of a power-up
+type PowerKind int
+
+// Number of power-up kinds available
+const NbPowerKinds = 4
+const (
+	SlowMotion PowerKind = iota // the snake moves at half speed
+	Ghost                       // the snake can go through its own body
+	Magnet                      // food close to the head is pulled toward it
+	Shield                      // the next wall the snake hits does not kill it
+)
+
+const (
+	powerUpLifetime = 8 * TicksPerSecond  // ticks a power-up stays on the board
+	powerUpDuration = 6 * TicksPerSecond  // ticks an effect lasts
+	shieldDuration  = 15 * TicksPerSecond // ticks a shield lasts if no wall is hit
+	minPowerUpDelay = 10 * TicksPerSecond // shortest wait between two power-ups
+	maxPowerUpDelay = 20 * TicksPerSecond // longest wait between two power-ups
+	magnetRange     = 5                   // tiles from the head within which food is pulled
+)
+
+// Duration returns the number of ticks the effect lasts once picked up
+func (k PowerKind) Duration() int {
+	if k == Shield {
+		return shieldDuration
+	}
+	return powerUpDuration
+}
+
+// PowerUp is a power-up waiting on the board to be picked up
+type PowerUp struct {
+	x, y    int
+	kind    PowerKind
+	expires int // tick on which it disappears
+}
+
+// Pos returns the position of the power-up on the board
+func (p *PowerUp) Pos() Point {
+	return Point{X: p.x, Y: p.y}
+}
+
+// Kind returns the effect of the power-up
+func (p *PowerUp) Kind() PowerKind {
+	return p.kind
+}
+
+// Expires returns the tick on which the power-up disappears
+func (p *PowerUp) Expires() int {
+	return p.expires
+}
+
+// PowerUp returns the power-up on the board, nil when there is none
+func (b *Board) PowerUp() *PowerUp {
+	return b.powerUp
+}
+
+// Effect returns the number of ticks left for the given effect on the
+// snake, 0 when it is not active
+func (b *Board) Effect(s *Snake, kind PowerKind) int {
+	return max(s.effects[kind]-b.ticks, 0)
+}
+
+// active reports whether the given effect is active on the snake
+func (b *Board) active(s *Snake, kind PowerKind) bool {
+	return b.Effect(s, kind) > 0
+}
+
+// updatePowerUp spawns and removes the power-up on the board
+func (b *Board) updatePowerUp() {
+	if !b.powerUps {
+		return
+	}
+
+	if b.powerUp != nil && b.ticks >= b.powerUp.expires {
+		b.powerUp = nil
+		b.nextPowerUp = b.ticks + b.powerUpDelay()
+	}
+
+	if b.powerUp == nil && b.ticks >= b.nextPowerUp {
+		p, ok := b.randomFreeTile()
+		if !ok {
+			return
+		}
+		b.powerUp = &PowerUp{
+			x:       p.X,
+			y:       p.Y,
+			kind:    PowerKind(b.rng.IntN(NbPowerKinds)),
+			expires: b.ticks + powerUpLifetime,
+		}
+	}
+}
+
+func (b *Board) powerUpDelay() int {
+	return minPowerUpDelay + b.rng.IntN(maxPowerUpDelay-minPowerUpDelay+1)
+}
+
+// pickUpPowerUp activates the power-up under the head of one of the snakes
+func (b *Board) pickUpPowerUp(snakes []*Snake) []Event {
+	if b.powerUp == nil {
+		return nil
+	}
+
+	for _, s := range snakes {
+		if s.Head() != b.powerUp.Pos() {
+			continue
+		}
+
+		kind := b.powerUp.kind
+		s.effects[kind] = b.ticks + kind.Duration()
+		b.powerUp = nil
+		b.nextPowerUp = b.ticks + b.powerUpDelay()
+		return []Event{{Kind: EventPowerUp, Snake: b.index(s), Pos: s.Head(), Power: kind}}
+	}
+	return nil
+}
+
+// pullFood moves the food one tile toward the head of the first snake with
+// an active magnet
+func (b *Board) pullFood(snakes []*Snake) {
+	for _, s := range snakes {
+		if b.active(s, Magnet) {
+			b.pullFoodTo(s.Head())
+			return
+		}
+	}
+}
+
+// pullFoodTo moves the food one tile toward the head when it is close enough
+func (b *Board) pullFoodTo(head Point) {
+	food := b.food.Pos()
+	dx, dy := head.X-food.X, head.Y-food.Y
+	if abs(dx)+abs(dy) > magnetRange {
+		return
+	}
+
+	// move along the axis the food is furthest on
+	next := food
+	if abs(dx) >= abs(dy) {
+		next.X += sign(dx)
+	} else {
+		next.Y += sign(dy)
+	}
+
+	if next == head || b.freeTile(next) {
+		b.food.x, b.food.y = next.X, next.Y
+	}
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
+
+func sign(n int) int {
+	switch {
+	case n > 0:
+		return 1
+	case n < 0:
+		return -1
+	}
+	return 0
+}
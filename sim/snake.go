@@ -0,0 +1,170 @@
+package sim
+
+// Maximum number of turns a snake keeps in its input queue
+const maxQueuedTurns = 3
+
+// Snake represents the snake
+type Snake struct {
+	body      []Point
+	direction Direction
+	color     Color
+	growth    int // segments left to grow by on the next moves
+	// turns waiting to be applied, one per move
+	queue   []Direction
+	bot     bool // not controlled by a player
+	dead    bool
+	score   int
+	eaten   int               // number of apples eaten, poison aside
+	effects [NbPowerKinds]int // tick on which each effect ends
+}
+
+// Length of a new snake
+const startLength = 3
+
+// Shortest a snake can be shrunk to, so it keeps a head and a tail
+const minLength = 2
+
+// newSnake creates a new snake with the given color, its head at the given
+// position facing the given direction
+func newSnake(head Point, dir Direction, color Color) *Snake {
+	return &Snake{
+		body:      startBody(head, dir),
+		direction: dir,
+		color:     color,
+	}
+}
+
+// startBody returns the body of a new snake from tail to head
+func startBody(head Point, dir Direction) []Point {
+	body := make([]Point, startLength)
+	for i := range body {
+		// segments are laid out behind the head
+		body[startLength-1-i] = head.Add(dir.Opposite(), i)
+	}
+	return body
+}
+
+// Head returns the position of the snake's head
+func (s *Snake) Head() Point {
+	return s.body[len(s.body)-1]
+}
+
+// Body returns the snake's segments from tail to head.
+// The returned slice must not be modified.
+func (s *Snake) Body() []Point {
+	return s.body
+}
+
+// Direction returns the direction the snake is moving in
+func (s *Snake) Direction() Direction {
+	return s.direction
+}
+
+// Color returns the snake's color
+func (s *Snake) Color() Color {
+	return s.color
+}
+
+// Turning reports whether the snake has turns waiting to be applied
+func (s *Snake) Turning() bool {
+	return len(s.queue) > 0
+}
+
+// Bot reports whether the snake is not controlled by a player
+func (s *Snake) Bot() bool {
+	return s.bot
+}
+
+// Alive reports whether the snake is still on the board
+func (s *Snake) Alive() bool {
+	return !s.dead
+}
+
+// Score returns the number of points the snake scored so far
+func (s *Snake) Score() int {
+	return s.score
+}
+
+// Eaten returns the number of apples the snake ate so far
+func (s *Snake) Eaten() int {
+	return s.eaten
+}
+
+// changeDirection queues a turn to be applied on a later move. Turns are
+// validated against the last queued direction so a quick "up then left" is
+// kept while reversing into the snake's own body is not.
+func (s *Snake) changeDirection(newDir Direction) {
+	last := s.direction
+	if len(s.queue) > 0 {
+		last = s.queue[len(s.queue)-1]
+	}
+
+	// Prevent the snake from reversing direction or queueing the same direction twice
+	if newDir == last || newDir.Opposite() == last || len(s.queue) >= maxQueuedTurns {
+		return
+	}
+	s.queue = append(s.queue, newDir)
+}
+
+// occupies reports whether one of the snake's segments is at the given position
+func (s *Snake) occupies(p Point) bool {
+	for _, b := range s.body {
+		if b == p {
+			return true
+		}
+	}
+	return false
+}
+
+// hitsBodyOf reports whether the snake's head is on one of the other snake's
+// segments besides its head
+func (s *Snake) hitsBodyOf(other *Snake) bool {
+	h := s.Head()
+	for _, b := range other.body[:len(other.body)-1] {
+		if b == h {
+			return true
+		}
+	}
+	return false
+}
+
+// headHits checks if the snake's head is at the given position
+func (s *Snake) headHits(x, y int) bool {
+	h := s.Head()
+
+	return h.X == x && h.Y == y
+}
+
+func (s *Snake) headHitsBody() bool {
+	return s.hitsBodyOf(s)
+}
+
+// grow makes the snake grow by n segments over its next moves, or shrinks
+// it right away when n is negative
+func (s *Snake) grow(n int) {
+	if n >= 0 {
+		s.growth += n
+		return
+	}
+
+	keep := max(len(s.body)+n, minLength)
+	s.body = s.body[len(s.body)-keep:]
+}
+
+// move applies the next queued turn and moves the snake one step in its direction
+func (s *Snake) move() {
+	if len(s.queue) > 0 {
+		s.direction = s.queue[0]
+		s.queue = s.queue[1:]
+	}
+
+	// Calculate the new position of the head based on the direction
+	newHead := s.Head().Add(s.direction, 1)
+
+	if s.growth > 0 {
+		s.body = append(s.body, newHead)
+		s.growth--
+	} else {
+		s.body = append(s.body[1:], newHead)
+	}
+}
@@ -0,0 +1,61 @@
+package sim
+
+// TicksPerSecond is the number of logical ticks in one second of game time,
+// matching the default update rate of Ebiten
+const TicksPerSecond = 60
+
+// SpeedCurve describes how many ticks pass between two moves of the snake
+type SpeedCurve struct {
+	Base          int `json:"base"`          // ticks between moves with no points
+	Min           int `json:"min"`           // ticks between moves at full speed
+	PointsPerTick int `json:"pointsPerTick"` // points needed to remove one tick from the interval, 0 keeps the speed constant
+}
+
+// DefaultSpeed starts at one move every 200ms and goes 5ms faster per point
+// until it reaches one move every 50ms
+var DefaultSpeed = SpeedCurve{Base: 12, Min: 3, PointsPerTick: 3}
+
+// Interval returns the number of ticks between two moves for the given score
+func (c SpeedCurve) Interval(score int) int {
+	interval := c.Base
+	if c.PointsPerTick > 0 {
+		// Decrease the interval linearly with the score
+		interval -= score / c.PointsPerTick
+	}
+
+	// Ensure the interval does not go below the minimum interval
+	if interval < c.Min {
+		interval = c.Min
+	}
+	if interval < 1 {
+		return 1
+	}
+	return interval
+}
+
+// Scheduler counts ticks and decides on which of them the snake moves
+type Scheduler struct {
+	speed SpeedCurve
+	wait  int // ticks elapsed since the last move
+}
+
+func newScheduler(speed SpeedCurve) *Scheduler {
+	return &Scheduler{speed: speed}
+}
+
+// Interval returns the number of ticks between two moves for the given score
+func (s *Scheduler) Interval(score int) int {
+	return s.speed.Interval(score)
+}
+
+// Tick advances the scheduler by one tick and reports whether the snake moves
+// on it, given the number of ticks between two moves
+func (s *Scheduler) Tick(interval int) bool {
+	s.wait++
+	if s.wait < interval {
+		return false
+	}
+
+	s.wait = 0
+	return true
+}
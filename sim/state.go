@@ -0,0 +1,184 @@
+package sim
+
+import (
+	"errors"
+	"math/rand/v2"
+)
+
+// State is a snapshot of a board that can be encoded and restored later
+type State struct {
+	Rows   int          `json:"rows"`
+	Cols   int          `json:"cols"`
+	Walls  Walls        `json:"walls"`
+	Level  *Level       `json:"level,omitempty"`
+	Goal   *Goal        `json:"goal,omitempty"`
+	Foods  FoodTable    `json:"foods,omitempty"` // chance of each kind of food to spawn
+	Snakes []SnakeState `json:"snakes"`
+	Food   FoodState    `json:"food"`
+	Power  bool         `json:"power,omitempty"` // whether power-ups spawn
+	// power-up waiting on the board, if any
+	PowerUp     *PowerUpState `json:"powerUp,omitempty"`
+	NextPowerUp int           `json:"nextPowerUp,omitempty"`
+	TimeAttack  *TimeAttack   `json:"timeAttack,omitempty"`
+	Deadline    int           `json:"deadline,omitempty"` // tick on which the time runs out
+	Ticks       int           `json:"ticks"`
+	Wait        int           `json:"wait"` // ticks since the last move
+	Speed       SpeedCurve    `json:"speed"`
+	Seed        uint64        `json:"seed"`
+	RNG         []byte        `json:"rng"`
+}
+
+// SnakeState is a snapshot of a snake
+type SnakeState struct {
+	Body      []Point           `json:"body"`
+	Direction Direction         `json:"direction"`
+	Color     Color             `json:"color"`
+	Growth    int               `json:"growth"`
+	Queue     []Direction       `json:"queue"`
+	Bot       bool              `json:"bot,omitempty"`
+	Dead      bool              `json:"dead,omitempty"`
+	Score     int               `json:"score"`
+	Eaten     int               `json:"eaten"`
+	Effects   [NbPowerKinds]int `json:"effects"` // tick on which each effect ends
+}
+
+// FoodState is a snapshot of the food
+type FoodState struct {
+	Pos     Point    `json:"pos"`
+	Kind    FoodKind `json:"kind"`
+	Expires int      `json:"expires"`
+}
+
+// PowerUpState is a snapshot of a power-up waiting on the board
+type PowerUpState struct {
+	Pos     Point     `json:"pos"`
+	Kind    PowerKind `json:"kind"`
+	Expires int       `json:"expires"`
+}
+
+// Snapshot returns the current state of the board
+func (b *Board) Snapshot() (State, error) {
+	rng, err := b.pcg.MarshalBinary()
+	if err != nil {
+		return State{}, err
+	}
+
+	var powerUp *PowerUpState
+	if b.powerUp != nil {
+		powerUp = &PowerUpState{
+			Pos:     b.powerUp.Pos(),
+			Kind:    b.powerUp.kind,
+			Expires: b.powerUp.expires,
+		}
+	}
+
+	snakes := make([]SnakeState, len(b.snakes))
+	for i, s := range b.snakes {
+		snakes[i] = SnakeState{
+			Body:      append([]Point(nil), s.body...),
+			Direction: s.direction,
+			Color:     s.color,
+			Growth:    s.growth,
+			Queue:     append([]Direction(nil), s.queue...),
+			Bot:       s.bot,
+			Dead:      s.dead,
+			Score:     s.score,
+			Eaten:     s.eaten,
+			Effects:   s.effects,
+		}
+	}
+
+	return State{
+		Rows:   b.rows,
+		Cols:   b.cols,
+		Walls:  b.walls,
+		Level:  b.level,
+		Goal:   b.goal,
+		Snakes: snakes,
+		Foods:  b.foodTable,
+		Food: FoodState{
+			Pos:     b.food.Pos(),
+			Kind:    b.food.kind,
+			Expires: b.food.expires,
+		},
+		Power:       b.powerUps,
+		PowerUp:     powerUp,
+		NextPowerUp: b.nextPowerUp,
+		TimeAttack:  b.timeAttack,
+		Deadline:    b.deadline,
+		Ticks:       b.ticks,
+		Wait:        b.scheduler.wait,
+		Speed:       b.scheduler.speed,
+		Seed:        b.seed,
+		RNG:         rng,
+	}, nil
+}
+
+// RestoreBoard creates a board from a snapshot taken with Snapshot
+func RestoreBoard(st State) (*Board, error) {
+	if st.Rows <= 0 || st.Cols <= 0 {
+		return nil, errors.New("invalid board dimensions")
+	}
+	if len(st.Snakes) == 0 {
+		return nil, errors.New("board has no snake")
+	}
+
+	snakes := make([]*Snake, len(st.Snakes))
+	for i, s := range st.Snakes {
+		if len(s.Body) == 0 {
+			return nil, errors.New("snake has no body")
+		}
+		snakes[i] = &Snake{
+			body:      append([]Point(nil), s.Body...),
+			direction: s.Direction,
+			color:     s.Color,
+			growth:    s.Growth,
+			queue:     append([]Direction(nil), s.Queue...),
+			bot:       s.Bot,
+			dead:      s.Dead,
+			score:     s.Score,
+			eaten:     s.Eaten,
+			effects:   s.Effects,
+		}
+	}
+
+	pcg := &rand.PCG{}
+	if err := pcg.UnmarshalBinary(st.RNG); err != nil {
+		return nil, err
+	}
+
+	scheduler := newScheduler(st.Speed)
+	scheduler.wait = st.Wait
+
+	var powerUp *PowerUp
+	if st.PowerUp != nil {
+		powerUp = &PowerUp{
+			x:       st.PowerUp.Pos.X,
+			y:       st.PowerUp.Pos.Y,
+			kind:    st.PowerUp.Kind,
+			expires: st.PowerUp.Expires,
+		}
+	}
+
+	return &Board{
+		rows:        st.Rows,
+		cols:        st.Cols,
+		walls:       st.Walls,
+		level:       st.Level,
+		obstacles:   st.Level.obstacleSet(),
+		goal:        st.Goal,
+		foodTable:   st.Foods,
+		food:        newFood(st.Food.Pos.X, st.Food.Pos.Y, st.Food.Kind, st.Food.Expires),
+		powerUps:    st.Power,
+		powerUp:     powerUp,
+		nextPowerUp: st.NextPowerUp,
+		timeAttack:  st.TimeAttack,
+		deadline:    st.Deadline,
+		snakes:      snakes,
+		ticks:       st.Ticks,
+		scheduler:   scheduler,
+		seed:        st.Seed,
+		pcg:         pcg,
+		rng:         rand.New(pcg),
+	}, nil
+}
@@ -0,0 +1,75 @@
+package sim_test
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/adan-ea/GoSnakeGo/sim"
+)
+
+func TestSnapshotRestore(t *testing.T) {
+	for seed := range uint64(10) {
+		b := sim.NewBoard(botConfig(seed))
+		play(b, botControllers(), 150)
+
+		st, err := b.Snapshot()
+		if err != nil {
+			t.Fatal(err)
+		}
+		// go through JSON like a saved game
+		data, err := json.Marshal(st)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var decoded sim.State
+		if err := json.Unmarshal(data, &decoded); err != nil {
+			t.Fatal(err)
+		}
+		restored, err := sim.RestoreBoard(decoded)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		again, err := restored.Snapshot()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(again, st) {
+			t.Fatalf("seed %d: restored board differs from its snapshot", seed)
+		}
+
+		// the rest of the game, food and power-ups included, plays the same
+		events := play(b, botControllers(), 20000)
+		restoredEvents := play(restored, botControllers(), 20000)
+		if !reflect.DeepEqual(restoredEvents, events) {
+			t.Fatalf("seed %d: restored board plays differently", seed)
+		}
+	}
+}
+
+func TestRestoreInvalid(t *testing.T) {
+	st, err := sim.NewBoard(sim.Config{Seed: 1}).Snapshot()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name   string
+		change func(st *sim.State)
+	}{
+		{"no rows", func(st *sim.State) { st.Rows = 0 }},
+		{"no snake", func(st *sim.State) { st.Snakes = nil }},
+		{"snake without a body", func(st *sim.State) { st.Snakes = []sim.SnakeState{{}} }},
+		{"bad rng", func(st *sim.State) { st.RNG = []byte("bad") }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bad := st
+			tt.change(&bad)
+			if _, err := sim.RestoreBoard(bad); err == nil {
+				t.Error("restored an invalid state")
+			}
+		})
+	}
+}
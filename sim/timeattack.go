@@ -0,0 +1,36 @@
+package sim
+
+// TimeAttack gives the player a time budget, the game ends when it runs out
+type TimeAttack struct {
+	Budget int `json:"budget"` // ticks the player starts with
+	Bonus  int `json:"bonus"`  // ticks added for each food eaten
+}
+
+// TimeAttack returns the time attack rules of the board, nil when there is no time limit
+func (b *Board) TimeAttack() *TimeAttack {
+	return b.timeAttack
+}
+
+// TimeLeft returns the number of ticks left before the time runs out, 0 without a time limit
+func (b *Board) TimeLeft() int {
+	if b.timeAttack == nil {
+		return 0
+	}
+	return max(b.deadline-b.ticks, 0)
+}
+
+// TimeUp reports whether the game ended because the time ran out
+func (b *Board) TimeUp() bool {
+	return b.timeUp
+}
+
+// checkTime ends the game when the time runs out
+func (b *Board) checkTime() []Event {
+	if b.gameOver || b.timeAttack == nil || b.ticks < b.deadline {
+		return nil
+	}
+
+	b.gameOver = true
+	b.timeUp = true
+	return []Event{{Kind: EventTimeUp, Pos: b.snakes[0].Head()}}
+}
@@ -0,0 +1,249 @@
+package sim
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Point represents a point in 2D space
+type Point struct {
+	X int `json:"x"`
+	Y int `json:"y"`
+}
+
+// Add returns the point n tiles away in the given direction
+func (p Point) Add(dir Direction, n int) Point {
+	switch dir {
+	case Up:
+		p.Y -= n
+	case Down:
+		p.Y += n
+	case Left:
+		p.X -= n
+	case Right:
+		p.X += n
+	}
+	return p
+}
+
+// Size represents the size of the board
+type Size int
+
+// Number of sizes available including Random and Custom
+const NbSize = 6
+const (
+	Small Size = iota
+	Medium
+	Large
+	ExtraLarge
+	RandomSize // one of the sizes above
+	CustomSize // any number of rows and columns, see Config
+)
+
+// Smallest and largest number of rows or columns of a custom board. Four
+// snakes start one behind the other along the edges, 8 tiles leave two free
+// tiles between the head of a snake and the tail of the next one and five
+// moves before the wall ahead.
+const (
+	MinSide = 8
+	MaxSide = 40
+)
+
+// GridSize returns the number of rows and columns of a board of the given size
+func GridSize(size Size) (int, int) {
+	switch size {
+	case Small:
+		return 14, 14
+	case Medium:
+		return 16, 16
+	case Large:
+		return 18, 18
+	case ExtraLarge:
+		return 20, 20
+	}
+	return 18, 18
+}
+
+// String returns the name of the size shown to the player
+func (s Size) String() string {
+	switch s {
+	case Small:
+		return "Small"
+	case Medium:
+		return "Medium"
+	case Large:
+		return "Large"
+	case ExtraLarge:
+		return "Extra Large"
+	case RandomSize:
+		return "Random"
+	case CustomSize:
+		return "Custom"
+	}
+	return "Large"
+}
+
+// ParseSize returns the size with the given name: small, medium, large,
+// extralarge or random
+func ParseSize(name string) (Size, error) {
+	switch strings.ToLower(name) {
+	case "small":
+		return Small, nil
+	case "medium":
+		return Medium, nil
+	case "large":
+		return Large, nil
+	case "extralarge", "extra large", "extra-large":
+		return ExtraLarge, nil
+	case "random":
+		return RandomSize, nil
+	}
+	return 0, fmt.Errorf("unknown size %q", name)
+}
+
+// SizeFromRowsCols returns the size matching the given grid dimensions,
+// CustomSize when none does
+func SizeFromRowsCols(rows, cols int) Size {
+	for size := Small; size <= ExtraLarge; size++ {
+		if r, c := GridSize(size); r == rows && c == cols {
+			return size
+		}
+	}
+	return CustomSize
+}
+
+// ParseDimensions returns the columns and rows of a board written as
+// WIDTHxHEIGHT, like 30x12
+func ParseDimensions(text string) (int, int, error) {
+	w, h, ok := strings.Cut(strings.ToLower(text), "x")
+	if !ok {
+		return 0, 0, fmt.Errorf("invalid dimensions %q, expected WIDTHxHEIGHT", text)
+	}
+	cols, err := strconv.Atoi(w)
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid width %q", w)
+	}
+	rows, err := strconv.Atoi(h)
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid height %q", h)
+	}
+	if cols < MinSide || cols > MaxSide || rows < MinSide || rows > MaxSide {
+		return 0, 0, fmt.Errorf("boards are between %d and %d tiles wide and high", MinSide, MaxSide)
+	}
+	return cols, rows, nil
+}
+
+// ClampSide brings a number of rows or columns within the limits of custom boards
+func ClampSide(n int) int {
+	return min(max(n, MinSide), MaxSide)
+}
+
+// Walls represents what happens when the snake reaches the edge of the board
+type Walls int
+
+// Number of wall behaviours available
+const NbWalls = 2
+const (
+	SolidWalls Walls = iota // the snake dies when it leaves the board
+	WrapWalls               // the snake comes back on the opposite edge
+)
+
+// Direction represents the direction of the snake's movement
+type Direction int
+
+const (
+	Right Direction = iota
+	Left
+	Down
+	Up
+)
+
+// Opposite returns the direction facing the other way
+func (d Direction) Opposite() Direction {
+	switch d {
+	case Up:
+		return Down
+	case Down:
+		return Up
+	case Left:
+		return Right
+	}
+	return Left
+}
+
+// String returns the name of the direction: up, down, left or right
+func (d Direction) String() string {
+	switch d {
+	case Up:
+		return "up"
+	case Down:
+		return "down"
+	case Left:
+		return "left"
+	}
+	return "right"
+}
+
+// ParseDirection returns the direction with the given name: up, down, left
+// or right
+func ParseDirection(name string) (Direction, error) {
+	switch strings.ToLower(name) {
+	case "up":
+		return Up, nil
+	case "down":
+		return Down, nil
+	case "left":
+		return Left, nil
+	case "right":
+		return Right, nil
+	}
+	return 0, fmt.Errorf("unknown direction %q", name)
+}
+
+// Color represents possible colors for the snake
+type Color int
+
+// Number of colors available including Random
+const NbColors = 4
+const (
+	Blue Color = iota
+	Purple
+	Red
+	RandomColor
+)
+
+// SetSize sets the size of the board from its name, see ParseSize, or from
+// its dimensions written as WIDTHxHEIGHT like 30x12
+func (c *Config) SetSize(text string) error {
+	if strings.ContainsAny(text, "0123456789") {
+		cols, rows, err := ParseDimensions(text)
+		if err != nil {
+			return err
+		}
+		c.Size, c.Cols, c.Rows = CustomSize, cols, rows
+		return nil
+	}
+
+	size, err := ParseSize(text)
+	if err != nil {
+		return err
+	}
+	c.Size = size
+	return nil
+}
+
+// ParseColor returns the color with the given name: blue, purple, red or random
+func ParseColor(name string) (Color, error) {
+	switch strings.ToLower(name) {
+	case "blue":
+		return Blue, nil
+	case "purple":
+		return Purple, nil
+	case "red":
+		return Red, nil
+	case "random":
+		return RandomColor, nil
+	}
+	return 0, fmt.Errorf("unknown color %q", name)
+}
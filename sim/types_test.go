@@ -0,0 +1,19 @@
+package sim_test
+
+import (
+	"testing"
+
+	"github.com/adan-ea/GoSnakeGo/sim"
+)
+
+func TestParseDirection(t *testing.T) {
+	for _, dir := range []sim.Direction{sim.Right, sim.Left, sim.Down, sim.Up} {
+		got, err := sim.ParseDirection(dir.String())
+		if err != nil || got != dir {
+			t.Errorf("ParseDirection(%q) = %v, %v", dir.String(), got, err)
+		}
+	}
+	if _, err := sim.ParseDirection("north"); err == nil {
+		t.Error("parsed an unknown direction")
+	}
+}
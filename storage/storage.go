@@ -0,0 +1,24 @@
+// Package storage locates the files the game keeps in the user config directory
+package storage
+
+import (
+	"os"
+	"path/filepath"
+)
+
+const appDir = "GoSnakeGo"
+
+// Dir returns the given directory inside the game's config directory,
+// creating it if needed
+func Dir(elem ...string) (string, error) {
+	base, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+
+	dir := filepath.Join(append([]string{base, appDir}, elem...)...)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", err
+	}
+	return dir, nil
+}
@@ -0,0 +1,55 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"strings"
+
+	"github.com/adan-ea/GoSnakeGo/resources/levels"
+	"github.com/adan-ea/GoSnakeGo/sim"
+	"github.com/adan-ea/GoSnakeGo/tui"
+)
+
+// runTUI plays in the terminal with the options given as arguments
+func runTUI(args []string) error {
+	fs := flag.NewFlagSet("tui", flag.ExitOnError)
+	size := fs.String("size", "large", "board size: small, medium, large, extralarge, random or WIDTHxHEIGHT")
+	color := fs.String("color", "blue", "snake color: blue, purple, red or random")
+	wrap := fs.Bool("wrap", false, "the snake comes back on the other side of the board")
+	level := fs.String("level", "", "name of the level to play")
+	seed := fs.Uint64("seed", 0, "seed of every game, random when not set")
+	fs.Parse(args)
+
+	var opts tui.Options
+	var board sim.Config
+	if err := board.SetSize(*size); err != nil {
+		return err
+	}
+	opts.Size, opts.Rows, opts.Cols = board.Size, board.Rows, board.Cols
+	var err error
+	if opts.Color, err = sim.ParseColor(*color); err != nil {
+		return err
+	}
+	if *wrap {
+		opts.Walls = sim.WrapWalls
+	}
+	fs.Visit(func(f *flag.Flag) {
+		if f.Name == "seed" {
+			opts.Seed, opts.HasSeed = *seed, true
+		}
+	})
+
+	if *level != "" {
+		levels.InitLevels()
+		for _, l := range levels.Levels {
+			if strings.EqualFold(l.Name, *level) {
+				opts.Level = l
+			}
+		}
+		if opts.Level == nil {
+			return fmt.Errorf("unknown level %q", *level)
+		}
+	}
+
+	return tui.Run(opts)
+}
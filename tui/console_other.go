@@ -0,0 +1,9 @@
+//go:build !windows
+
+package tui
+
+// enableVirtualTerminal does nothing, terminals outside of Windows understand
+// ANSI escape codes
+func enableVirtualTerminal() (func(), error) {
+	return func() {}, nil
+}
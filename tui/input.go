@@ -0,0 +1,101 @@
+package tui
+
+import (
+	"io"
+
+	"github.com/adan-ea/GoSnakeGo/sim"
+)
+
+// key is a key the terminal game reacts to
+type key int
+
+const (
+	keyNone key = iota
+	keySpace
+	keyPause
+	keyEscape
+	keyQuit
+	// the keys steering the snake, in the order of sim's directions
+	keyRight
+	keyLeft
+	keyDown
+	keyUp
+)
+
+// direction returns the direction the key steers the snake to
+func (k key) direction() (sim.Direction, bool) {
+	if k < keyRight {
+		return 0, false
+	}
+	return sim.Right + sim.Direction(k-keyRight), true
+}
+
+// readKeys sends the keys read from r until it fails, which quits the game
+func readKeys(r io.Reader, keys chan<- key) {
+	buf := make([]byte, 64)
+	for {
+		n, err := r.Read(buf)
+		if err != nil {
+			keys <- keyQuit
+			return
+		}
+		for _, k := range parseKeys(buf[:n]) {
+			keys <- k
+		}
+	}
+}
+
+// parseKeys returns the keys in the bytes read at once from a raw terminal.
+// Arrows come as escape sequences, an escape alone is the Escape key.
+func parseKeys(data []byte) []key {
+	var keys []key
+	for i := 0; i < len(data); i++ {
+		c := data[i]
+		if c == 0x1b {
+			// ESC [ A or ESC O A depending on the terminal's cursor mode
+			if i+2 < len(data) && (data[i+1] == '[' || data[i+1] == 'O') {
+				keys = append(keys, arrowKey(data[i+2]))
+				i += 2
+				continue
+			}
+			keys = append(keys, keyEscape)
+			continue
+		}
+		keys = append(keys, charKey(c))
+	}
+	return keys
+}
+
+func arrowKey(c byte) key {
+	switch c {
+	case 'A':
+		return keyUp
+	case 'B':
+		return keyDown
+	case 'C':
+		return keyRight
+	case 'D':
+		return keyLeft
+	}
+	return keyNone
+}
+
+func charKey(c byte) key {
+	switch c {
+	case 'w', 'W':
+		return keyUp
+	case 's', 'S':
+		return keyDown
+	case 'a', 'A':
+		return keyLeft
+	case 'd', 'D':
+		return keyRight
+	case ' ':
+		return keySpace
+	case 'p', 'P':
+		return keyPause
+	case 'q', 'Q', 0x03: // Ctrl+C does not stop a raw terminal
+		return keyQuit
+	}
+	return keyNone
+}
@@ -0,0 +1,44 @@
+package tui
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/adan-ea/GoSnakeGo/sim"
+)
+
+func TestParseKeys(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+		keys []key
+	}{
+		{"letters", "wasd", []key{keyUp, keyLeft, keyDown, keyRight}},
+		{"upper case", "WQ", []key{keyUp, keyQuit}},
+		{"arrows", "\x1b[A\x1b[B\x1b[C\x1b[D", []key{keyUp, keyDown, keyRight, keyLeft}},
+		{"application mode arrows", "\x1bOA\x1bOD", []key{keyUp, keyLeft}},
+		{"escape alone", "\x1b", []key{keyEscape}},
+		{"escape then a key", "\x1bp", []key{keyEscape, keyPause}},
+		{"cut sequence", "\x1b[", []key{keyEscape, keyNone}},
+		{"unknown sequence", "\x1b[Z ", []key{keyNone, keySpace}},
+		{"ctrl+c", "\x03", []key{keyQuit}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if keys := parseKeys([]byte(tt.data)); !slices.Equal(keys, tt.keys) {
+				t.Errorf("keys %v, want %v", keys, tt.keys)
+			}
+		})
+	}
+}
+
+func TestKeyDirection(t *testing.T) {
+	dirs := map[key]sim.Direction{keyUp: sim.Up, keyDown: sim.Down, keyLeft: sim.Left, keyRight: sim.Right}
+	for k := keyNone; k <= keyUp; k++ {
+		dir, ok := k.direction()
+		if want, steers := dirs[k]; ok != steers || dir != want {
+			t.Errorf("key %d steers %v %v, want %v %v", k, dir, ok, want, steers)
+		}
+	}
+}
@@ -0,0 +1,124 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/adan-ea/GoSnakeGo/sim"
+)
+
+// Escape codes switching to the alternate screen with a hidden cursor and
+// back
+const (
+	enterScreen = "\x1b[?1049h\x1b[?25l\x1b[2J"
+	leaveScreen = "\x1b[0m\x1b[?25h\x1b[?1049l"
+	home        = "\x1b[H"
+	clearLine   = "\x1b[K"
+	reset       = "\x1b[0m"
+)
+
+// Every tile is two characters wide so the board looks square
+const (
+	emptyTile    = "  "
+	obstacleTile = "\x1b[90m▒▒" + reset
+	bodyTile     = "██"
+	foodTile     = "◖◗"
+)
+
+// snakeColors are the ANSI colors of the body and the head of each snake color
+var snakeColors = [sim.NbColors - 1][2]string{
+	sim.Blue:   {"\x1b[34m", "\x1b[94m"},
+	sim.Purple: {"\x1b[35m", "\x1b[95m"},
+	sim.Red:    {"\x1b[31m", "\x1b[91m"},
+}
+
+// foodColor returns the ANSI color of a kind of food
+func foodColor(kind sim.FoodKind) string {
+	switch kind {
+	case sim.GoldenApple:
+		return "\x1b[93m"
+	case sim.PoisonApple:
+		return "\x1b[92m"
+	case sim.BonusApple:
+		return "\x1b[96m"
+	case sim.BigApple:
+		return "\x1b[33m"
+	}
+	return "\x1b[91m"
+}
+
+// draw writes the board to the terminal if it changed since the last frame
+func (g *game) draw() error {
+	frame := g.render()
+	if frame == g.frame {
+		return nil
+	}
+	g.frame = frame
+
+	g.out.WriteString(home)
+	g.out.WriteString(frame)
+	return g.out.Flush()
+}
+
+// render returns the whole screen, lines end with \r\n because a raw
+// terminal does not go back to the first column on its own
+func (g *game) render() string {
+	b := g.board
+	tiles := make([][]string, b.Rows())
+	for y := range tiles {
+		tiles[y] = make([]string, b.Cols())
+		for x := range tiles[y] {
+			tiles[y][x] = emptyTile
+			if b.Obstacle(sim.Point{X: x, Y: y}) {
+				tiles[y][x] = obstacleTile
+			}
+		}
+	}
+	set := func(p sim.Point, tile string) {
+		if b.InBounds(p) {
+			tiles[p.Y][p.X] = tile
+		}
+	}
+
+	food := b.Food()
+	set(food.Pos(), foodColor(food.Kind())+foodTile+reset)
+	for _, s := range b.Snakes() {
+		if !s.Alive() && s != b.Snake() {
+			continue
+		}
+		colors := snakeColors[s.Color()]
+		for _, p := range s.Body() {
+			set(p, colors[0]+bodyTile+reset)
+		}
+		set(s.Head(), colors[1]+bodyTile+reset)
+	}
+
+	var sb strings.Builder
+	border := strings.Repeat("─", 2*b.Cols())
+	sb.WriteString("┌" + border + "┐" + clearLine + "\r\n")
+	for _, row := range tiles {
+		sb.WriteString("│" + strings.Join(row, "") + "│" + clearLine + "\r\n")
+	}
+	sb.WriteString("└" + border + "┘" + clearLine + "\r\n")
+
+	fmt.Fprintf(&sb, " Score %d   Best %d   Seed %d%s\r\n", b.Score(), g.highScore, b.Seed(), clearLine)
+	if g.saveErr != nil {
+		sb.WriteString(" Score not saved, see the error when quitting" + clearLine + "\r\n")
+	}
+	sb.WriteString(" " + g.status() + clearLine + "\r\n")
+	return sb.String()
+}
+
+// status returns the line telling the player what they can do
+func (g *game) status() string {
+	switch g.mode {
+	case modePause:
+		return "Paused, P or Space to resume, Q to quit"
+	case modeGameOver:
+		if g.board.Won() {
+			return "You filled the board! Space to play again, Q to quit"
+		}
+		return "Game over, Space to play again, Q to quit"
+	}
+	return "Arrows or WASD to move, P to pause, Q to quit"
+}
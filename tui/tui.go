@@ -0,0 +1,168 @@
+// Package tui plays the game in a terminal, drawing the board with ANSI
+// escape codes and Unicode characters
+package tui
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/adan-ea/GoSnakeGo/scoreboard"
+	"github.com/adan-ea/GoSnakeGo/sim"
+	"golang.org/x/term"
+)
+
+// Options are the settings the terminal game is launched with
+type Options struct {
+	Size    sim.Size
+	Rows    int // rows of a sim.CustomSize board
+	Cols    int // columns of a sim.CustomSize board
+	Color   sim.Color
+	Walls   sim.Walls
+	Level   *sim.Level // played instead of an empty board when set
+	Seed    uint64     // seed used for every game when HasSeed is set
+	HasSeed bool
+}
+
+// state of the terminal game
+type mode int
+
+const (
+	modeGame mode = iota
+	modePause
+	modeGameOver
+)
+
+// game is a terminal game in progress
+type game struct {
+	opts      Options
+	board     *sim.Board
+	mode      mode
+	table     string // scoreboard table of the board, empty when scores are not kept
+	highScore int
+	queue     []sim.Direction // directions pressed but not given to the snake yet
+	out       *bufio.Writer
+	frame     string // last frame drawn
+	saveErr   error  // why a score could not be saved, reported when quitting
+}
+
+// Run plays in the terminal until the player quits. The standard input must
+// be a terminal.
+func Run(opts Options) error {
+	fd := int(os.Stdin.Fd())
+	if !term.IsTerminal(fd) {
+		return errors.New("the standard input is not a terminal")
+	}
+	restoreConsole, err := enableVirtualTerminal()
+	if err != nil {
+		return err
+	}
+	defer restoreConsole()
+
+	old, err := term.MakeRaw(fd)
+	if err != nil {
+		return err
+	}
+	defer term.Restore(fd, old)
+
+	g := &game{opts: opts, out: bufio.NewWriter(os.Stdout)}
+	g.out.WriteString(enterScreen)
+	defer func() {
+		g.out.WriteString(leaveScreen)
+		g.out.Flush()
+	}()
+
+	keys := make(chan key, 16)
+	go readKeys(os.Stdin, keys)
+
+	g.newBoard()
+	ticker := time.NewTicker(time.Second / sim.TicksPerSecond)
+	defer ticker.Stop()
+	for {
+		select {
+		case k := <-keys:
+			if k == keyQuit {
+				// returned after the deferred calls restored the terminal
+				return g.saveErr
+			}
+			g.handleKey(k)
+		case <-ticker.C:
+			g.update()
+			if err := g.draw(); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// newBoard starts a new game with the options of the terminal game
+func (g *game) newBoard() {
+	seed := g.opts.Seed
+	if !g.opts.HasSeed {
+		seed = sim.NewSeed()
+	}
+
+	g.board = sim.NewBoard(sim.Config{
+		Size:  g.opts.Size,
+		Rows:  g.opts.Rows,
+		Cols:  g.opts.Cols,
+		Color: g.opts.Color,
+		Walls: g.opts.Walls,
+		Level: g.opts.Level,
+		Food:  sim.FoodFor(g.opts.Walls),
+		Seed:  seed,
+	})
+	g.table = scoreboard.Table(g.board)
+	g.highScore = scoreboard.Best(g.table)
+	g.queue = nil
+	g.mode = modeGame
+}
+
+// handleKey reacts to a key pressed by the player
+func (g *game) handleKey(k key) {
+	switch g.mode {
+	case modeGame:
+		if dir, ok := k.direction(); ok {
+			g.queue = append(g.queue, dir)
+		} else if k == keyPause || k == keyEscape {
+			g.mode = modePause
+		}
+	case modePause:
+		if k == keyPause || k == keyEscape || k == keySpace {
+			g.mode = modeGame
+		}
+	case modeGameOver:
+		if k == keySpace {
+			g.newBoard()
+		}
+	}
+}
+
+// update advances the game by one tick, giving the snake the oldest
+// direction pressed
+func (g *game) update() {
+	if g.mode != modeGame {
+		return
+	}
+
+	var action sim.Action
+	if len(g.queue) > 0 {
+		action = sim.Action{Turn: true, Dir: g.queue[0]}
+		g.queue = g.queue[1:]
+	}
+	g.board.Tick(action)
+	if score := g.board.Score(); score > g.highScore {
+		g.highScore = score
+	}
+
+	if g.board.GameOver() {
+		if g.table != "" {
+			if err := scoreboard.Save(g.board.Score(), g.table); err != nil {
+				g.saveErr = fmt.Errorf("score not saved: %w", err)
+			}
+		}
+		g.mode = modeGameOver
+	}
+}